@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/session"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// authFlags holds the session flags shared by every scan command
+type authFlags struct {
+	cookie       string
+	cookieFile   string
+	loginURL     string
+	loginMethod  string
+	loginBody    string
+	loginSuccess string
+	loggedOut    string
+	tokenURL     string
+	tokenMethod  string
+	tokenBody    string
+	tokenField   string
+}
+
+func (a *authFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&a.cookie, "cookie", "", "Raw Cookie header value sent with every request")
+	fs.StringVar(&a.cookieFile, "cookie-file", "", "Netscape cookies.txt file to load")
+	fs.StringVar(&a.loginURL, "login-url", "", "Login request URL run before scanning")
+	fs.StringVar(&a.loginMethod, "login-method", "POST", "Login request method")
+	fs.StringVar(&a.loginBody, "login-body", "", "Login request body (JSON or form encoded)")
+	fs.StringVar(&a.loginSuccess, "login-success", "", "Matcher for a successful login (status:302, header:Name:regex, or body regex)")
+	fs.StringVar(&a.loggedOut, "logged-out", "", "Matcher for logged-out responses; triggers a re-login and replay")
+	fs.StringVar(&a.tokenURL, "token-url", "", "Token endpoint for bearer authentication")
+	fs.StringVar(&a.tokenMethod, "token-method", "POST", "Token request method")
+	fs.StringVar(&a.tokenBody, "token-body", "", "Token request body (JSON or form encoded)")
+	fs.StringVar(&a.tokenField, "token-field", "access_token", "Dotted JSON path of the token in the token response")
+}
+
+func (a *authFlags) enabled() bool {
+	return a.cookie != "" || a.cookieFile != "" || a.loginURL != "" || a.tokenURL != ""
+}
+
+func (a *authFlags) validate() error {
+	if a.cookieFile != "" && !utils.PathExists(a.cookieFile) {
+		return fmt.Errorf("cookie file not found: %s", a.cookieFile)
+	}
+	for flagName, value := range map[string]string{"--login-url": a.loginURL, "--token-url": a.tokenURL} {
+		if value == "" {
+			continue
+		}
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid %s %q: expected an absolute http(s) URL", flagName, value)
+		}
+	}
+	if a.loginSuccess != "" && a.loginURL == "" {
+		return fmt.Errorf("--login-success requires --login-url")
+	}
+	if a.loggedOut != "" && a.loginURL == "" && a.tokenURL == "" {
+		return fmt.Errorf("--logged-out requires --login-url or --token-url")
+	}
+	return nil
+}
+
+// buildSession turns the auth flags into a session. It returns nil when no
+// authentication was requested.
+func (a *authFlags) buildSession(cfg *config.Config) (*session.Session, error) {
+	if !a.enabled() {
+		return nil, nil
+	}
+
+	opts := session.Options{
+		Cookie:     a.cookie,
+		CookieFile: a.cookieFile,
+		TokenField: a.tokenField,
+		Timeout:    cfg.Timeout,
+		Proxy:      cfg.Proxy,
+		UserAgent:  cfg.UserAgent,
+		Headers:    cfg.Headers,
+		Scope:      cfg.Scope,
+	}
+
+	if a.loginURL != "" {
+		opts.Login = &session.Request{
+			URL:    a.loginURL,
+			Method: strings.ToUpper(a.loginMethod),
+			Body:   a.loginBody,
+		}
+	}
+	if a.tokenURL != "" {
+		opts.Token = &session.Request{
+			URL:    a.tokenURL,
+			Method: strings.ToUpper(a.tokenMethod),
+			Body:   a.tokenBody,
+		}
+	}
+
+	var err error
+	if a.loginSuccess != "" {
+		if opts.LoginSuccess, err = session.ParseMatcher(a.loginSuccess); err != nil {
+			return nil, fmt.Errorf("invalid --login-success: %w", err)
+		}
+	}
+	if a.loggedOut != "" {
+		if opts.LoggedOut, err = session.ParseMatcher(a.loggedOut); err != nil {
+			return nil, fmt.Errorf("invalid --logged-out: %w", err)
+		}
+	}
+
+	return session.New(opts)
+}
+
+// startSession logs in before the scan starts and reports later re-logins.
+func startSession(cfg *config.Config, printer *output.Printer) error {
+	if cfg.Session == nil {
+		return nil
+	}
+
+	if err := cfg.Session.Start(); err != nil {
+		printer.Error("Authentication failed: %v", err)
+		return err
+	}
+	printer.Info("Authenticated session ready")
+
+	cfg.Session.OnRenew(func(err error) {
+		if err != nil {
+			printer.Warning("Re-login after logged-out response failed: %v", err)
+			return
+		}
+		printer.Info("Session expired; logged in again")
+	})
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// command describes a subcommand, its flag groups, and its entry point
+type command struct {
+	name     string
+	summary  string
+	examples []string
+	groups   flagGroup
+	newFlags func(c *command) *flag.FlagSet
+	run      func(args []string) error
+}
+
+// commands is populated in init to avoid an initialization cycle between the
+// run functions and parseGlobalFlags, which looks commands up by name.
+var commands []*command
+
+func init() {
+	scanFlags := func(c *command) *flag.FlagSet {
+		fs, _ := newScanFlagSet(c)
+		return fs
+	}
+
+	commands = []*command{
+		{
+			name:    "bypass",
+			summary: "Test for 403/401 bypass techniques (inspired by nomore403)",
+			examples: []string{
+				"httpsuite bypass -u https://example.com/admin",
+				"httpsuite bypass -u https://example.com/admin --techniques headers,endpaths --bypass-ip 10.0.0.1",
+			},
+			groups:   groupBypass,
+			newFlags: scanFlags,
+			run:      runBypass,
+		},
+		{
+			name:     "crlf",
+			summary:  "Test for CRLF injection vulnerabilities (inspired by crlfuzz)",
+			examples: []string{"httpsuite crlf -u https://example.com", "cat urls.txt | httpsuite crlf -c 50"},
+			newFlags: scanFlags,
+			run:      runCRLF,
+		},
+		{
+			name:     "cors",
+			summary:  "Test for CORS misconfiguration (inspired by corser)",
+			examples: []string{"httpsuite cors -u https://example.com --deep --origin https://attacker.com"},
+			groups:   groupCORS,
+			newFlags: scanFlags,
+			run:      runCORS,
+		},
+		{
+			name:     "methods",
+			summary:  "Test allowed HTTP methods on targets (inspired by httpc)",
+			examples: []string{"httpsuite methods -u https://example.com --methods GET,POST,PUT --status 200,405"},
+			groups:   groupMethods,
+			newFlags: scanFlags,
+			run:      runMethods,
+		},
+		{
+			name:     "smuggle",
+			summary:  "Test for HTTP request smuggling via H2 downgrade (inspired by smugglefuzz)",
+			examples: []string{"httpsuite smuggle -u https://example.com --extended --interval 10"},
+			groups:   groupSmuggle,
+			newFlags: scanFlags,
+			run:      runSmuggle,
+		},
+		{
+			name:    "all",
+			summary: "Run all modules against target(s)",
+			examples: []string{
+				"httpsuite all -u https://example.com -c 20",
+				"httpsuite all -u https://example.com --modules bypass,cors --origin https://attacker.com",
+				"httpsuite all -u https://example.com --skip smuggle --wordlist gadgets.txt",
+			},
+			groups:   groupBypass | groupCORS | groupMethods | groupSmuggle | groupAll,
+			newFlags: scanFlags,
+			run:      runAll,
+		},
+		{
+			name:    "verify",
+			summary: "Replay bypass findings from an earlier JSON output and report which still reproduce",
+			examples: []string{
+				"httpsuite verify --input results.json",
+				"httpsuite verify --input results.jsonl --replays 5 -u https://example.com/admin",
+			},
+			groups:   groupVerify,
+			newFlags: scanFlags,
+			run:      runVerify,
+		},
+		{
+			name:     "sync-payloads",
+			summary:  "Download current upstream payload files into a local payload directory",
+			examples: []string{"httpsuite sync-payloads --payload-dir payloads-custom"},
+			newFlags: func(c *command) *flag.FlagSet {
+				fs, _ := newSyncFlagSet(c)
+				return fs
+			},
+			run: runSyncPayloads,
+		},
+		{
+			name:     "completion",
+			summary:  "Generate shell completion for bash, zsh, or fish",
+			examples: []string{"source <(httpsuite completion bash)", "httpsuite completion fish > ~/.config/fish/completions/httpsuite.fish"},
+			newFlags: func(c *command) *flag.FlagSet {
+				fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+				fs.Usage = func() { printCommandUsage(fs, c) }
+				return fs
+			},
+			run: runCompletion,
+		},
+	}
+}
+
+// lookupCommand returns the command with the given name, or nil
+func lookupCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// printCommandUsage prints per-command help for -h and 'httpsuite help <command>'
+func printCommandUsage(fs *flag.FlagSet, c *command) {
+	out := fs.Output()
+	fmt.Fprintf(out, "Usage:\n  httpsuite %s [flags]\n\n%s\n", c.name, c.summary)
+	if c.name == "completion" {
+		fmt.Fprintf(out, "\nArguments:\n  bash|zsh|fish   Shell to generate completion for\n")
+	}
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(out, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if len(c.examples) > 0 {
+		fmt.Fprintf(out, "\nExamples:\n")
+		for _, example := range c.examples {
+			fmt.Fprintf(out, "  %s\n", example)
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// runHelp prints general usage or the help for a single command
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return nil
+	}
+
+	c := lookupCommand(args[0])
+	if c == nil {
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+
+	fs := c.newFlags(c)
+	fs.SetOutput(os.Stdout)
+	printCommandUsage(fs, c)
+	return nil
+}
+
+// flagNames returns the flags of a command formatted as they are typed
+func flagNames(c *command) []string {
+	var names []string
+	c.newFlags(c).VisitAll(func(f *flag.Flag) {
+		names = append(names, flagSpelling(f.Name))
+	})
+	return names
+}
+
+func flagSpelling(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands)+2)
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	return append(names, "help", "version")
+}
+
+func joinWords(words []string) string {
+	return strings.Join(words, " ")
+}
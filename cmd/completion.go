@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aether-0/httpsuite/internal/bypass"
+)
+
+// fileFlags take a filesystem path and complete file names
+var fileFlags = map[string]bool{
+	"l":                 true,
+	"o":                 true,
+	"r":                 true,
+	"config":            true,
+	"data-file":         true,
+	"import":            true,
+	"scope-file":        true,
+	"out-of-scope-file": true,
+	"cookie-file":       true,
+	"wordlist":          true,
+	"payload-dir":       true,
+}
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// runCompletion prints a completion script for the requested shell
+func runCompletion(args []string) error {
+	c := lookupCommand("completion")
+	fs := c.newFlags(c)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("completion requires one shell argument: %s", strings.Join(completionShells, ", "))
+	}
+
+	switch fs.Arg(0) {
+	case "bash":
+		writeBashCompletion(os.Stdout)
+	case "zsh":
+		writeZshCompletion(os.Stdout)
+	case "fish":
+		writeFishCompletion(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: %s)", fs.Arg(0), strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer) {
+	var fileCases []string
+	for name := range fileFlags {
+		fileCases = append(fileCases, flagSpelling(name))
+	}
+	sort.Strings(fileCases)
+
+	fmt.Fprintf(w, `# bash completion for httpsuite
+_httpsuite() {
+    local cur prev cmd
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    if [[ ${COMP_CWORD} -eq 1 ]]; then
+        COMPREPLY=( $(compgen -W "%s" -- "${cur}") )
+        return
+    fi
+
+    case "${prev}" in
+        %s)
+            COMPREPLY=( $(compgen -f -- "${cur}") )
+            return
+            ;;
+        --techniques)
+            local prefix=""
+            [[ "${cur}" == *,* ]] && prefix="${cur%%,*},"
+            COMPREPLY=( $(compgen -P "${prefix}" -W "%s" -- "${cur##*,}") )
+            return
+            ;;
+    esac
+
+    cmd="${COMP_WORDS[1]}"
+    case "${cmd}" in
+`, joinWords(commandNames()), strings.Join(fileCases, "|"), joinWords(bypass.Techniques))
+
+	for _, c := range commands {
+		words := flagNames(c)
+		if c.name == "completion" {
+			words = completionShells
+		}
+		fmt.Fprintf(w, "        %s)\n            COMPREPLY=( $(compgen -W \"%s\" -- \"${cur}\") )\n            ;;\n", c.name, joinWords(words))
+	}
+	fmt.Fprintf(w, "        help)\n            COMPREPLY=( $(compgen -W \"%s\" -- \"${cur}\") )\n            ;;\n", joinWords(commandNames()))
+
+	fmt.Fprint(w, `    esac
+}
+complete -F _httpsuite httpsuite
+`)
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprint(w, "#compdef httpsuite\n\n_httpsuite() {\n    local -a commands\n    commands=(\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.name, zshEscape(c.summary))
+	}
+	fmt.Fprint(w, "        'help:Show help for a command'\n        'version:Show version'\n    )\n\n")
+	fmt.Fprint(w, "    if (( CURRENT == 2 )); then\n        _describe 'command' commands\n        return\n    fi\n\n")
+	fmt.Fprint(w, "    local cmd=${words[2]}\n    shift words\n    (( CURRENT-- ))\n\n    case ${cmd} in\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(w, "        %s)\n            _arguments \\\n", c.name)
+		if c.name == "completion" {
+			fmt.Fprintf(w, "                '1:shell:(%s)'\n            ;;\n", joinWords(completionShells))
+			continue
+		}
+		var specs []string
+		c.newFlags(c).VisitAll(func(f *flag.Flag) {
+			specs = append(specs, "'"+zshFlagSpec(f)+"'")
+		})
+		fmt.Fprintf(w, "                %s\n            ;;\n", strings.Join(specs, " \\\n                "))
+	}
+	fmt.Fprint(w, "        help)\n            _describe 'command' commands\n            ;;\n")
+	fmt.Fprint(w, "    esac\n}\n\ncompdef _httpsuite httpsuite\n")
+}
+
+func zshFlagSpec(f *flag.Flag) string {
+	spec := flagSpelling(f.Name) + "[" + zshEscape(f.Usage) + "]"
+	if f.Name == "H" {
+		spec = "*" + spec
+	}
+
+	switch {
+	case isBoolFlag(f):
+		return spec
+	case fileFlags[f.Name]:
+		return spec + ":path:_files"
+	case f.Name == "techniques":
+		return spec + ":techniques:_values -s , technique " + joinWords(bypass.Techniques)
+	default:
+		return spec + ":value:"
+	}
+}
+
+func zshEscape(s string) string {
+	replacer := strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:")
+	return replacer.Replace(s)
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprint(w, "# fish completion for httpsuite\ncomplete -c httpsuite -f\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "complete -c httpsuite -n __fish_use_subcommand -a %s -d '%s'\n", c.name, fishEscape(c.summary))
+	}
+	fmt.Fprint(w, "complete -c httpsuite -n __fish_use_subcommand -a help -d 'Show help for a command'\n")
+	fmt.Fprint(w, "complete -c httpsuite -n __fish_use_subcommand -a version -d 'Show version'\n")
+
+	for _, c := range commands {
+		condition := "'__fish_seen_subcommand_from " + c.name + "'"
+		if c.name == "completion" {
+			fmt.Fprintf(w, "complete -c httpsuite -n %s -a '%s'\n", condition, joinWords(completionShells))
+			continue
+		}
+
+		c.newFlags(c).VisitAll(func(f *flag.Flag) {
+			option := "-l " + f.Name
+			if len(f.Name) == 1 {
+				option = "-s " + f.Name
+			}
+
+			extra := ""
+			switch {
+			case isBoolFlag(f):
+			case fileFlags[f.Name]:
+				extra = " -r -F"
+			case f.Name == "techniques":
+				extra = " -r -a '" + joinWords(bypass.Techniques) + "'"
+			default:
+				extra = " -r"
+			}
+
+			fmt.Fprintf(w, "complete -c httpsuite -n %s %s%s -d '%s'\n", condition, option, extra, fishEscape(f.Usage))
+		})
+	}
+}
+
+func fishEscape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(s)
+}
@@ -0,0 +1,439 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig mirrors the scan flags that can be set from a --config file.
+// Pointer fields distinguish "not set" from zero values so that profiles and
+// CLI flags only override what they mention.
+type fileConfig struct {
+	URL            *string           `yaml:"url" toml:"url"`
+	List           *string           `yaml:"list" toml:"list"`
+	Request        *string           `yaml:"request" toml:"request"`
+	Import         []string          `yaml:"import" toml:"import"`
+	ImportBase     *string           `yaml:"import-base" toml:"import-base"`
+	Data           *string           `yaml:"data" toml:"data"`
+	Scope          []string          `yaml:"scope" toml:"scope"`
+	ScopeFile      *string           `yaml:"scope-file" toml:"scope-file"`
+	OutOfScope     []string          `yaml:"out-of-scope" toml:"out-of-scope"`
+	OutOfScopeFile *string           `yaml:"out-of-scope-file" toml:"out-of-scope-file"`
+	DataFile       *string           `yaml:"data-file" toml:"data-file"`
+	ContentType    *string           `yaml:"content-type" toml:"content-type"`
+	Method         *string           `yaml:"method" toml:"method"`
+	Concurrency    *int              `yaml:"concurrency" toml:"concurrency"`
+	Timeout        *int              `yaml:"timeout" toml:"timeout"`
+	Retries        *int              `yaml:"retries" toml:"retries"`
+	Proxy          *string           `yaml:"proxy" toml:"proxy"`
+	Headers        map[string]string `yaml:"headers" toml:"headers"`
+	Output         *string           `yaml:"output" toml:"output"`
+	JSON           *bool             `yaml:"json" toml:"json"`
+	Format         []string          `yaml:"format" toml:"format"`
+	MinSeverity    *string           `yaml:"min-severity" toml:"min-severity"`
+	Unique         *bool             `yaml:"unique" toml:"unique"`
+	Silent         *bool             `yaml:"silent" toml:"silent"`
+	Verbose        *bool             `yaml:"verbose" toml:"verbose"`
+	NoColor        *bool             `yaml:"no-color" toml:"no-color"`
+	NoProgress     *bool             `yaml:"no-progress" toml:"no-progress"`
+	Redirect       *bool             `yaml:"redirect" toml:"redirect"`
+	PayloadDir     *string           `yaml:"payload-dir" toml:"payload-dir"`
+	UserAgent      *string           `yaml:"user-agent" toml:"user-agent"`
+	RandomAgent    *bool             `yaml:"random-agent" toml:"random-agent"`
+	PerHost        *bool             `yaml:"per-host" toml:"per-host"`
+	Preflight      *bool             `yaml:"preflight" toml:"preflight"`
+
+	Bypass  bypassFileConfig  `yaml:"bypass" toml:"bypass"`
+	CORS    corsFileConfig    `yaml:"cors" toml:"cors"`
+	Methods methodsFileConfig `yaml:"methods" toml:"methods"`
+	Smuggle smuggleFileConfig `yaml:"smuggle" toml:"smuggle"`
+	Verify  verifyFileConfig  `yaml:"verify" toml:"verify"`
+	All     allFileConfig     `yaml:"all" toml:"all"`
+	Auth    authFileConfig    `yaml:"auth" toml:"auth"`
+
+	Profile  string                `yaml:"profile" toml:"profile"`
+	Profiles map[string]fileConfig `yaml:"profiles" toml:"profiles"`
+}
+
+type bypassFileConfig struct {
+	Techniques  []string `yaml:"techniques" toml:"techniques"`
+	BypassIP    *string  `yaml:"bypass-ip" toml:"bypass-ip"`
+	ChainTop    *int     `yaml:"chain-top" toml:"chain-top"`
+	ChainBudget *int     `yaml:"chain-budget" toml:"chain-budget"`
+	TargetForm  *string  `yaml:"target-form" toml:"target-form"`
+}
+
+type corsFileConfig struct {
+	Origin *string `yaml:"origin" toml:"origin"`
+	Deep   *bool   `yaml:"deep" toml:"deep"`
+}
+
+type methodsFileConfig struct {
+	Methods []string `yaml:"methods" toml:"methods"`
+	Status  []int    `yaml:"status" toml:"status"`
+}
+
+type smuggleFileConfig struct {
+	Extended *bool   `yaml:"extended" toml:"extended"`
+	Wordlist *string `yaml:"wordlist" toml:"wordlist"`
+	Interval *int    `yaml:"interval" toml:"interval"`
+}
+
+type verifyFileConfig struct {
+	Replays *int `yaml:"replays" toml:"replays"`
+}
+
+type allFileConfig struct {
+	Modules []string `yaml:"modules" toml:"modules"`
+	Skip    []string `yaml:"skip" toml:"skip"`
+}
+
+type authFileConfig struct {
+	Cookie       *string `yaml:"cookie" toml:"cookie"`
+	CookieFile   *string `yaml:"cookie-file" toml:"cookie-file"`
+	LoginURL     *string `yaml:"login-url" toml:"login-url"`
+	LoginMethod  *string `yaml:"login-method" toml:"login-method"`
+	LoginBody    *string `yaml:"login-body" toml:"login-body"`
+	LoginSuccess *string `yaml:"login-success" toml:"login-success"`
+	LoggedOut    *string `yaml:"logged-out" toml:"logged-out"`
+	TokenURL     *string `yaml:"token-url" toml:"token-url"`
+	TokenMethod  *string `yaml:"token-method" toml:"token-method"`
+	TokenBody    *string `yaml:"token-body" toml:"token-body"`
+	TokenField   *string `yaml:"token-field" toml:"token-field"`
+}
+
+func intPtr(v int) *int       { return &v }
+func boolPtr(v bool) *bool    { return &v }
+func strPtr(v string) *string { return &v }
+
+// builtinProfiles are available without a config file and can be redefined by one.
+var builtinProfiles = map[string]fileConfig{
+	"stealth": {
+		Concurrency: intPtr(2),
+		Timeout:     intPtr(20),
+		Retries:     intPtr(2),
+		RandomAgent: boolPtr(true),
+		Bypass: bypassFileConfig{
+			Techniques: []string{"headers", "endpaths", "midpaths"},
+		},
+	},
+	"aggressive": {
+		Concurrency: intPtr(50),
+		Timeout:     intPtr(5),
+		CORS:        corsFileConfig{Deep: boolPtr(true)},
+		Smuggle:     smuggleFileConfig{Extended: boolPtr(true)},
+	},
+}
+
+// loadFileConfig reads a YAML or TOML config file. The format is chosen by
+// extension; anything other than .toml is parsed as YAML (which also accepts JSON).
+// Unknown keys are rejected so that a misspelled setting is not silently ignored.
+func loadFileConfig(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var fc fileConfig
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		meta, err := toml.Decode(string(data), &fc)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing TOML config %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return nil, fmt.Errorf("error parsing TOML config %s: unknown keys %s", path, strings.Join(keys, ", "))
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing YAML config %s: %w", path, err)
+		}
+	}
+
+	return &fc, nil
+}
+
+// resolveProfile returns the file settings with the named profile layered on
+// top. An empty name falls back to the file's own "profile" key.
+func (fc *fileConfig) resolveProfile(name string) (*fileConfig, error) {
+	if name == "" {
+		name = fc.Profile
+	}
+	if name == "" {
+		return fc, nil
+	}
+
+	profile, ok := fc.Profiles[name]
+	if !ok {
+		profile, ok = builtinProfiles[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(fc.profileNames(), ", "))
+	}
+
+	merged := *fc
+	merged.overlay(&profile)
+	return &merged, nil
+}
+
+func (fc *fileConfig) profileNames() []string {
+	seen := make(map[string]struct{})
+	for name := range builtinProfiles {
+		seen[name] = struct{}{}
+	}
+	for name := range fc.Profiles {
+		seen[name] = struct{}{}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// overlay copies every field set in other onto fc.
+func (fc *fileConfig) overlay(other *fileConfig) {
+	overlayPtr(&fc.URL, other.URL)
+	overlayPtr(&fc.List, other.List)
+	overlayPtr(&fc.Request, other.Request)
+	overlayPtr(&fc.ImportBase, other.ImportBase)
+	overlayPtr(&fc.Data, other.Data)
+	overlayPtr(&fc.DataFile, other.DataFile)
+	overlayPtr(&fc.ContentType, other.ContentType)
+	overlayPtr(&fc.Method, other.Method)
+	overlayPtr(&fc.Concurrency, other.Concurrency)
+	overlayPtr(&fc.Timeout, other.Timeout)
+	overlayPtr(&fc.Retries, other.Retries)
+	overlayPtr(&fc.Proxy, other.Proxy)
+	overlayPtr(&fc.Output, other.Output)
+	overlayPtr(&fc.JSON, other.JSON)
+	overlayPtr(&fc.MinSeverity, other.MinSeverity)
+	overlayPtr(&fc.Unique, other.Unique)
+	overlayPtr(&fc.Silent, other.Silent)
+	overlayPtr(&fc.Verbose, other.Verbose)
+	overlayPtr(&fc.NoColor, other.NoColor)
+	overlayPtr(&fc.NoProgress, other.NoProgress)
+	overlayPtr(&fc.Redirect, other.Redirect)
+	overlayPtr(&fc.PayloadDir, other.PayloadDir)
+	overlayPtr(&fc.UserAgent, other.UserAgent)
+	overlayPtr(&fc.RandomAgent, other.RandomAgent)
+	overlayPtr(&fc.PerHost, other.PerHost)
+	overlayPtr(&fc.Preflight, other.Preflight)
+	overlayPtr(&fc.Bypass.BypassIP, other.Bypass.BypassIP)
+	overlayPtr(&fc.Bypass.ChainTop, other.Bypass.ChainTop)
+	overlayPtr(&fc.Bypass.ChainBudget, other.Bypass.ChainBudget)
+	overlayPtr(&fc.Bypass.TargetForm, other.Bypass.TargetForm)
+	overlayPtr(&fc.CORS.Origin, other.CORS.Origin)
+	overlayPtr(&fc.CORS.Deep, other.CORS.Deep)
+	overlayPtr(&fc.Smuggle.Extended, other.Smuggle.Extended)
+	overlayPtr(&fc.Smuggle.Wordlist, other.Smuggle.Wordlist)
+	overlayPtr(&fc.Smuggle.Interval, other.Smuggle.Interval)
+	overlayPtr(&fc.Verify.Replays, other.Verify.Replays)
+	overlayPtr(&fc.Auth.Cookie, other.Auth.Cookie)
+	overlayPtr(&fc.Auth.CookieFile, other.Auth.CookieFile)
+	overlayPtr(&fc.Auth.LoginURL, other.Auth.LoginURL)
+	overlayPtr(&fc.Auth.LoginMethod, other.Auth.LoginMethod)
+	overlayPtr(&fc.Auth.LoginBody, other.Auth.LoginBody)
+	overlayPtr(&fc.Auth.LoginSuccess, other.Auth.LoginSuccess)
+	overlayPtr(&fc.Auth.LoggedOut, other.Auth.LoggedOut)
+	overlayPtr(&fc.Auth.TokenURL, other.Auth.TokenURL)
+	overlayPtr(&fc.Auth.TokenMethod, other.Auth.TokenMethod)
+	overlayPtr(&fc.Auth.TokenBody, other.Auth.TokenBody)
+	overlayPtr(&fc.Auth.TokenField, other.Auth.TokenField)
+
+	if len(other.Bypass.Techniques) > 0 {
+		fc.Bypass.Techniques = other.Bypass.Techniques
+	}
+	if len(other.Scope) > 0 {
+		fc.Scope = other.Scope
+	}
+	if len(other.OutOfScope) > 0 {
+		fc.OutOfScope = other.OutOfScope
+	}
+	overlayPtr(&fc.ScopeFile, other.ScopeFile)
+	overlayPtr(&fc.OutOfScopeFile, other.OutOfScopeFile)
+	if len(other.Import) > 0 {
+		fc.Import = other.Import
+	}
+	if len(other.Format) > 0 {
+		fc.Format = other.Format
+	}
+	if len(other.Methods.Methods) > 0 {
+		fc.Methods.Methods = other.Methods.Methods
+	}
+	if len(other.Methods.Status) > 0 {
+		fc.Methods.Status = other.Methods.Status
+	}
+	if len(other.All.Modules) > 0 {
+		fc.All.Modules = other.All.Modules
+	}
+	if len(other.All.Skip) > 0 {
+		fc.All.Skip = other.All.Skip
+	}
+
+	if len(other.Headers) > 0 {
+		headers := make(map[string]string, len(fc.Headers)+len(other.Headers))
+		for k, v := range fc.Headers {
+			headers[k] = v
+		}
+		for k, v := range other.Headers {
+			headers[k] = v
+		}
+		fc.Headers = headers
+	}
+}
+
+func overlayPtr[T any](dst **T, src *T) {
+	if src != nil {
+		*dst = src
+	}
+}
+
+// flagValues converts the file settings into flag name/value pairs.
+func (fc *fileConfig) flagValues() map[string]string {
+	values := make(map[string]string)
+
+	setStr := func(name string, v *string) {
+		if v != nil {
+			values[name] = *v
+		}
+	}
+	setInt := func(name string, v *int) {
+		if v != nil {
+			values[name] = strconv.Itoa(*v)
+		}
+	}
+	setBool := func(name string, v *bool) {
+		if v != nil {
+			values[name] = strconv.FormatBool(*v)
+		}
+	}
+	setList := func(name string, v []string) {
+		if len(v) > 0 {
+			values[name] = strings.Join(v, ",")
+		}
+	}
+
+	setStr("u", fc.URL)
+	setStr("l", fc.List)
+	setStr("r", fc.Request)
+	setList("import", fc.Import)
+	setStr("import-base", fc.ImportBase)
+	setStr("data", fc.Data)
+	setList("scope", fc.Scope)
+	setStr("scope-file", fc.ScopeFile)
+	setList("out-of-scope", fc.OutOfScope)
+	setStr("out-of-scope-file", fc.OutOfScopeFile)
+	setStr("data-file", fc.DataFile)
+	setStr("content-type", fc.ContentType)
+	setStr("X", fc.Method)
+	setInt("c", fc.Concurrency)
+	setInt("t", fc.Timeout)
+	setInt("retries", fc.Retries)
+	setStr("x", fc.Proxy)
+	setStr("o", fc.Output)
+	setBool("j", fc.JSON)
+	setList("format", fc.Format)
+	setStr("min-severity", fc.MinSeverity)
+	setBool("unique", fc.Unique)
+	setBool("s", fc.Silent)
+	setBool("v", fc.Verbose)
+	setBool("no-color", fc.NoColor)
+	setBool("no-progress", fc.NoProgress)
+	setBool("redirect", fc.Redirect)
+	setStr("payload-dir", fc.PayloadDir)
+	setStr("ua", fc.UserAgent)
+	setBool("random-agent", fc.RandomAgent)
+	setBool("per-host", fc.PerHost)
+	setBool("preflight", fc.Preflight)
+
+	setList("techniques", fc.Bypass.Techniques)
+	setStr("bypass-ip", fc.Bypass.BypassIP)
+	setInt("chain-top", fc.Bypass.ChainTop)
+	setInt("chain-budget", fc.Bypass.ChainBudget)
+	setStr("target-form", fc.Bypass.TargetForm)
+	setStr("origin", fc.CORS.Origin)
+	setBool("deep", fc.CORS.Deep)
+	setList("methods", fc.Methods.Methods)
+	if len(fc.Methods.Status) > 0 {
+		codes := make([]string, 0, len(fc.Methods.Status))
+		for _, code := range fc.Methods.Status {
+			codes = append(codes, strconv.Itoa(code))
+		}
+		values["status"] = strings.Join(codes, ",")
+	}
+	setBool("extended", fc.Smuggle.Extended)
+	setStr("wordlist", fc.Smuggle.Wordlist)
+	setInt("interval", fc.Smuggle.Interval)
+	setInt("replays", fc.Verify.Replays)
+	setList("modules", fc.All.Modules)
+	setList("skip", fc.All.Skip)
+	setStr("cookie", fc.Auth.Cookie)
+	setStr("cookie-file", fc.Auth.CookieFile)
+	setStr("login-url", fc.Auth.LoginURL)
+	setStr("login-method", fc.Auth.LoginMethod)
+	setStr("login-body", fc.Auth.LoginBody)
+	setStr("login-success", fc.Auth.LoginSuccess)
+	setStr("logged-out", fc.Auth.LoggedOut)
+	setStr("token-url", fc.Auth.TokenURL)
+	setStr("token-method", fc.Auth.TokenMethod)
+	setStr("token-body", fc.Auth.TokenBody)
+	setStr("token-field", fc.Auth.TokenField)
+
+	return values
+}
+
+// applyFileConfig loads the config file and profile and sets every flag the
+// user did not pass explicitly. It returns the headers from the file so they
+// can be merged underneath any -H values.
+func applyFileConfig(fs *flag.FlagSet, path, profile string) (map[string]string, error) {
+	var fc *fileConfig
+	if path != "" {
+		loaded, err := loadFileConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		fc = loaded
+	} else {
+		fc = &fileConfig{}
+	}
+
+	resolved, err := fc.resolveProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for name, value := range resolved.flagValues() {
+		if explicit[name] || fs.Lookup(name) == nil {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return nil, fmt.Errorf("invalid config value for %s: %w", name, err)
+		}
+	}
+
+	return resolved.Headers, nil
+}
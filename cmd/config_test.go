@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleYAMLConfig = `concurrency: 15
+timeout: 7
+headers:
+  Authorization: Bearer file-token
+  X-Team: red
+bypass:
+  techniques: [headers, endpaths]
+cors:
+  origin: https://attacker.example
+profiles:
+  stealth:
+    concurrency: 1
+    headers:
+      X-Team: blue
+`
+
+func TestParseGlobalFlagsAppliesConfigProfileAndCLIOverrides(t *testing.T) {
+	path := writeConfigFile(t, "scan.yaml", sampleYAMLConfig)
+
+	cfg, opts, err := parseGlobalFlags([]string{
+		"--config", path,
+		"--profile", "stealth",
+		"-t", "3",
+		"-H", "Authorization: Bearer cli-token",
+		"-u", "https://example.com/admin",
+	}, "bypass")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+
+	if cfg.Concurrency != 1 {
+		t.Fatalf("expected profile concurrency 1, got %d", cfg.Concurrency)
+	}
+	if cfg.Timeout != 3*time.Second {
+		t.Fatalf("expected CLI timeout to win, got %s", cfg.Timeout)
+	}
+	if cfg.Headers["Authorization"] != "Bearer cli-token" {
+		t.Fatalf("expected CLI header override, got %q", cfg.Headers["Authorization"])
+	}
+	if cfg.Headers["X-Team"] != "blue" {
+		t.Fatalf("expected profile header, got %q", cfg.Headers["X-Team"])
+	}
+	if opts.techniques != "headers,endpaths" {
+		t.Fatalf("unexpected techniques: %q", opts.techniques)
+	}
+
+	_, opts, err = parseGlobalFlags([]string{"--config", path, "-u", "example.com"}, "cors")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if opts.origin != "https://attacker.example" {
+		t.Fatalf("unexpected origin: %q", opts.origin)
+	}
+}
+
+func TestParseGlobalFlagsReadsTOMLConfig(t *testing.T) {
+	path := writeConfigFile(t, "scan.toml", `
+concurrency = 4
+
+[smuggle]
+extended = true
+interval = 9
+
+[methods]
+status = [200, 405]
+`)
+
+	cfg, opts, err := parseGlobalFlags([]string{"--config", path, "-u", "example.com"}, "smuggle")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+
+	if cfg.Concurrency != 4 {
+		t.Fatalf("expected concurrency 4, got %d", cfg.Concurrency)
+	}
+	if !opts.extended || opts.interval != 9 {
+		t.Fatalf("unexpected smuggle options: extended=%v interval=%d", opts.extended, opts.interval)
+	}
+
+	_, opts, err = parseGlobalFlags([]string{"--config", path, "-u", "example.com"}, "methods")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if opts.filterStatus != "200,405" {
+		t.Fatalf("unexpected status filter: %q", opts.filterStatus)
+	}
+}
+
+func TestParseGlobalFlagsRejectsUnknownProfile(t *testing.T) {
+	path := writeConfigFile(t, "scan.yaml", sampleYAMLConfig)
+
+	if _, _, err := parseGlobalFlags([]string{"--config", path, "--profile", "missing"}, "bypass"); err == nil {
+		t.Fatalf("expected unknown profile error")
+	}
+}
+
+func TestParseGlobalFlagsRejectsUnknownConfigKeys(t *testing.T) {
+	for _, tc := range []struct{ name, content, key string }{
+		{"scan.yaml", "concurency: 5\n", "concurency"},
+		{"scan.toml", "[bypass]\ntechnique = [\"headers\"]\n", "bypass.technique"},
+	} {
+		path := writeConfigFile(t, tc.name, tc.content)
+		_, _, err := parseGlobalFlags([]string{"--config", path, "-u", "example.com"}, "bypass")
+		if err == nil || !strings.Contains(err.Error(), tc.key) {
+			t.Fatalf("%s: expected an error naming %s, got %v", tc.name, tc.key, err)
+		}
+	}
+
+	// An empty file sets nothing
+	path := writeConfigFile(t, "empty.yaml", "")
+	if _, _, err := parseGlobalFlags([]string{"--config", path, "-u", "example.com"}, "bypass"); err != nil {
+		t.Fatalf("expected an empty config to load, got %v", err)
+	}
+}
+
+func TestBuiltinProfileWithoutConfigFile(t *testing.T) {
+	cfg, _, err := parseGlobalFlags([]string{"--profile", "aggressive", "-u", "example.com"}, "cors")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+
+	if cfg.Concurrency != 50 {
+		t.Fatalf("expected aggressive concurrency 50, got %d", cfg.Concurrency)
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
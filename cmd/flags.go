@@ -0,0 +1,544 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aether-0/httpsuite/internal/bypass"
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/importer"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/rawrequest"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// flagGroup identifies a set of module-specific flags a command accepts
+type flagGroup int
+
+const (
+	groupBypass flagGroup = 1 << iota
+	groupCORS
+	groupMethods
+	groupSmuggle
+	groupAll
+	groupVerify
+)
+
+var defaultTechniques = strings.Join(bypass.Techniques, ",")
+
+// scanModules lists the modules run by 'all', in execution order
+var scanModules = []string{"bypass", "crlf", "cors", "methods", "smuggle"}
+
+// multiFlag allows repeating -H flags
+type multiFlag []string
+
+func (m *multiFlag) String() string { return strings.Join(*m, ", ") }
+func (m *multiFlag) Set(val string) error {
+	*m = append(*m, val)
+	return nil
+}
+
+// scanOptions holds module-specific settings shared by the scan commands
+type scanOptions struct {
+	techniques   string
+	bypassIP     string
+	chainTop     int
+	chainBudget  int
+	targetForm   string
+	origin       string
+	deepScan     bool
+	methodList   string
+	filterStatus string
+	extended     bool
+	gadgetFile   string
+	interval     int
+	modules      string
+	skip         string
+	perHost      bool
+	input        string
+	replays      int
+}
+
+// scanFlags holds the raw values of a scan command's flag set before they are
+// folded into config.Config.
+type scanFlags struct {
+	cfg         *config.Config
+	opts        *scanOptions
+	headers     multiFlag
+	proxyStr    string
+	timeoutSec  int
+	listFile    string
+	requestFile string
+	data        string
+	dataFile    string
+	contentType string
+	imports     string
+	importBase  string
+	formats     string
+	configFile  string
+	profile     string
+	auth        authFlags
+	scope       scopeFlags
+}
+
+// newScanFlagSet builds the flag set for a scan command: global flags plus
+// only the module flag groups that command understands.
+func newScanFlagSet(c *command) (*flag.FlagSet, *scanFlags) {
+	sf := &scanFlags{
+		cfg:  config.Default(),
+		opts: &scanOptions{},
+	}
+	cfg := sf.cfg
+	opts := sf.opts
+
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() { printCommandUsage(fs, c) }
+
+	fs.StringVar(&sf.configFile, "config", "", "YAML or TOML configuration file")
+	fs.StringVar(&sf.profile, "profile", "", "Named profile from the configuration file")
+	fs.StringVar(&cfg.URL, "u", "", "Target URL")
+	fs.StringVar(&sf.listFile, "l", "", "File containing list of URLs")
+	fs.StringVar(&sf.requestFile, "r", "", "Raw HTTP request file (e.g. saved from Burp) used as the base request")
+	fs.StringVar(&sf.imports, "import", "", "Comma-separated HAR, OpenAPI/Swagger or sitemap.xml files or URLs to import targets from")
+	fs.StringVar(&sf.importBase, "import-base", "", "Base URL for imported OpenAPI paths (overrides the spec's servers)")
+	fs.StringVar(&sf.data, "data", "", "Request body for the base request (implies POST unless -X is set)")
+	fs.StringVar(&sf.dataFile, "data-file", "", "File containing the request body")
+	fs.StringVar(&sf.contentType, "content-type", "", "Content-Type of the request body (guessed when empty)")
+	fs.StringVar(&cfg.Method, "X", cfg.Method, "HTTP method for the base request")
+	fs.IntVar(&cfg.Concurrency, "c", 10, "Concurrency level")
+	fs.IntVar(&sf.timeoutSec, "t", 10, "Timeout in seconds")
+	fs.IntVar(&cfg.Retries, "retries", cfg.Retries, "Attempts per request")
+	fs.StringVar(&sf.proxyStr, "x", "", "Proxy URL")
+	fs.Var(&sf.headers, "H", "Custom header (Key: Value), repeatable")
+	fs.StringVar(&cfg.OutputFile, "o", "", "Output file")
+	fs.BoolVar(&cfg.JSONOutput, "j", false, "JSON output")
+	fs.StringVar(&sf.formats, "format", "", "Comma-separated report formats written to -o: text, json, jsonl, sarif, markdown, html")
+	fs.StringVar(&cfg.MinSeverity, "min-severity", "", "Only report results at or above this severity: info, low, medium, high, critical")
+	fs.BoolVar(&cfg.Unique, "unique", false, "Print one result per target, status and response fingerprint, with a count and payload list")
+	fs.BoolVar(&cfg.Silent, "s", false, "Silent mode")
+	fs.BoolVar(&cfg.Verbose, "v", false, "Verbose mode")
+	fs.BoolVar(&cfg.NoColor, "no-color", false, "Disable color")
+	fs.BoolVar(&cfg.NoProgress, "no-progress", false, "Disable the live progress display")
+	fs.BoolVar(&cfg.Redirect, "redirect", false, "Follow redirects")
+	fs.StringVar(&cfg.PayloadDir, "payload-dir", cfg.PayloadDir, "Local payload override directory")
+	fs.StringVar(&cfg.UserAgent, "ua", "httpsuite/1.0", "User-Agent string")
+	fs.BoolVar(&cfg.RandomAgent, "random-agent", false, "Use random User-Agent")
+	fs.BoolVar(&cfg.PreflightEnabled, "preflight", false, "Probe liveness, TLS, HTTP/2, WAF and wildcard 404s per host before scanning")
+	sf.auth.register(fs)
+	sf.scope.register(fs)
+
+	if c.groups&groupBypass != 0 {
+		fs.StringVar(&opts.techniques, "techniques", defaultTechniques, "Comma-separated bypass techniques")
+		fs.StringVar(&opts.bypassIP, "bypass-ip", "", "Custom IP for header-based bypass")
+		fs.IntVar(&opts.chainTop, "chain-top", bypass.DefaultChainTop, "Partial results per verb, header and path kind combined by the chain technique")
+		fs.IntVar(&opts.chainBudget, "chain-budget", bypass.DefaultChainBudget, "Maximum requests sent by the chain technique")
+		fs.StringVar(&opts.targetForm, "target-form", bypass.TargetFormOrigin, "Request-target form for raw path payloads: origin or absolute")
+	}
+	if c.groups&groupCORS != 0 {
+		fs.StringVar(&opts.origin, "origin", "https://evil.com", "Custom origin for CORS testing")
+		fs.BoolVar(&opts.deepScan, "deep", false, "Enable deep CORS scan")
+	}
+	if c.groups&groupMethods != 0 {
+		fs.StringVar(&opts.methodList, "methods", "", "Comma-separated HTTP methods")
+		fs.StringVar(&opts.filterStatus, "status", "", "Comma-separated status codes to report")
+	}
+	if c.groups&groupSmuggle != 0 {
+		fs.BoolVar(&opts.extended, "extended", false, "Use extended gadget list")
+		fs.StringVar(&opts.gadgetFile, "wordlist", "", "Custom gadget file")
+		fs.IntVar(&opts.interval, "interval", 5, "Detection timeout in seconds")
+	}
+	if c.groups&(groupCORS|groupSmuggle) != 0 {
+		fs.BoolVar(&opts.perHost, "per-host", false, "Test only the first URL of each host (CORS and smuggle)")
+	}
+	if c.groups&groupVerify != 0 {
+		fs.StringVar(&opts.input, "input", "", "JSON or JSON Lines output of an earlier scan")
+		fs.IntVar(&opts.replays, "replays", 3, "Times each finding is replayed")
+	}
+	if c.groups&groupAll != 0 {
+		fs.StringVar(&opts.modules, "modules", strings.Join(scanModules, ","), "Comma-separated modules to run")
+		fs.StringVar(&opts.skip, "skip", "", "Comma-separated modules to exclude")
+	}
+
+	return fs, sf
+}
+
+// parseGlobalFlags parses the flags of the named scan command. Values from
+// --config and --profile fill in any flag not given on the command line.
+func parseGlobalFlags(args []string, name string) (*config.Config, *scanOptions, error) {
+	c := lookupCommand(name)
+	if c == nil {
+		return nil, nil, fmt.Errorf("unknown command: %s", name)
+	}
+
+	fs, sf := newScanFlagSet(c)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, nil, fmt.Errorf("unexpected argument %q (see 'httpsuite %s -h')", fs.Arg(0), name)
+	}
+
+	cfg := sf.cfg
+	opts := sf.opts
+
+	cliFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		cliFlags[f.Name] = true
+	})
+
+	if sf.configFile != "" || sf.profile != "" {
+		fileHeaders, err := applyFileConfig(fs, sf.configFile, sf.profile)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range fileHeaders {
+			cfg.Headers[k] = v
+		}
+	}
+
+	if err := validateScanFlags(c, sf); err != nil {
+		return nil, nil, err
+	}
+
+	cfg.Timeout = time.Duration(sf.timeoutSec) * time.Second
+
+	for _, format := range splitList(strings.ToLower(sf.formats)) {
+		if !containsString(cfg.Formats, format) {
+			cfg.Formats = append(cfg.Formats, format)
+		}
+	}
+
+	var baseRequest *rawrequest.Request
+	if sf.requestFile != "" {
+		req, err := rawrequest.Load(sf.requestFile, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		applyBaseRequest(cfg, req, cliFlags)
+		baseRequest = req
+	}
+
+	if err := applyRequestBody(cfg, sf, cliFlags, baseRequest != nil); err != nil {
+		return nil, nil, err
+	}
+
+	cfg.Method = strings.ToUpper(cfg.Method)
+
+	if sf.proxyStr != "" {
+		cfg.ProxyStr = sf.proxyStr
+		p, err := url.Parse(sf.proxyStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		cfg.Proxy = p
+	}
+
+	// Parse custom headers
+	for _, h := range sf.headers {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) == 2 {
+			cfg.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	// Collect URLs
+	if cfg.URL != "" {
+		cfg.URLs = append(cfg.URLs, utils.CanonicalURL(cfg.URL))
+	}
+
+	if sf.listFile != "" {
+		lines, err := utils.ReadLines(sf.listFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading URL list: %w", err)
+		}
+		for _, line := range lines {
+			cfg.URLs = append(cfg.URLs, utils.CanonicalURL(line))
+		}
+	}
+
+	if sf.imports != "" {
+		if err := importTargets(cfg, sf); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// The request file's own URL is the target unless targets were given
+	if baseRequest != nil && len(cfg.URLs) == 0 {
+		cfg.URLs = append(cfg.URLs, baseRequest.URL)
+	}
+
+	if utils.HasStdin() && len(cfg.URLs) == 0 {
+		cfg.URLs = utils.ReadURLsFromStdin()
+	}
+
+	if cfg.RandomAgent {
+		cfg.UserAgent = utils.RandomUserAgent()
+	}
+
+	rules, err := sf.scope.build()
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.Scope = rules
+
+	sess, err := sf.auth.buildSession(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.Session = sess
+
+	return cfg, opts, nil
+}
+
+// applyBaseRequest makes a parsed request file the base request of the scan.
+// Flags given on the command line (-X, -H, -ua) still take precedence.
+// Preflight headers of a saved OPTIONS request are not replayed.
+func applyBaseRequest(cfg *config.Config, req *rawrequest.Request, cliFlags map[string]bool) {
+	if !cliFlags["X"] {
+		cfg.Method = req.Method
+	}
+
+	for k, v := range req.Headers {
+		if strings.HasPrefix(strings.ToLower(k), "access-control-request-") {
+			continue
+		}
+		if strings.EqualFold(k, "User-Agent") {
+			if !cliFlags["ua"] && !cfg.RandomAgent {
+				cfg.UserAgent = v
+			}
+			continue
+		}
+		cfg.Headers[k] = v
+	}
+
+	cfg.Body = req.Body
+	cfg.ContentType = req.ContentType
+}
+
+// importTargets adds the targets of every --import source after the plain
+// URLs. Plain URLs keep the base request; imported ones bring their own.
+func importTargets(cfg *config.Config, sf *scanFlags) error {
+	targets := make([]common.Target, 0, len(cfg.URLs))
+	for _, u := range cfg.URLs {
+		targets = append(targets, common.Target{URL: u})
+	}
+
+	opts := importer.Options{BaseURL: sf.importBase, Timeout: cfg.Timeout, Proxy: cfg.Proxy}
+	for _, source := range splitList(sf.imports) {
+		imported, err := importer.Load(source, opts)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, imported...)
+	}
+
+	cfg.Targets = targets
+	cfg.URLs = cfg.URLs[:0]
+	seen := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		if !seen[t.URL] {
+			seen[t.URL] = true
+			cfg.URLs = append(cfg.URLs, t.URL)
+		}
+	}
+	return nil
+}
+
+// applyRequestBody sets the body from --data or --data-file, which replace the
+// body of a request file. Like curl, a body turns the default GET into POST.
+func applyRequestBody(cfg *config.Config, sf *scanFlags, cliFlags map[string]bool, fromRequestFile bool) error {
+	var body []byte
+	switch {
+	case sf.dataFile != "":
+		data, err := os.ReadFile(sf.dataFile)
+		if err != nil {
+			return fmt.Errorf("error reading data file: %w", err)
+		}
+		body = data
+	case sf.data != "":
+		body = []byte(sf.data)
+	}
+
+	if body != nil {
+		cfg.Body = body
+		if !cliFlags["X"] && !fromRequestFile && strings.EqualFold(cfg.Method, "GET") {
+			cfg.Method = "POST"
+		}
+	}
+
+	if sf.contentType != "" {
+		cfg.ContentType = sf.contentType
+	} else if len(cfg.Body) > 0 && cfg.ContentType == "" {
+		cfg.ContentType = utils.GuessContentType(cfg.Body)
+	}
+	return nil
+}
+
+// validateScanFlags rejects invalid values before any request is sent.
+func validateScanFlags(c *command, sf *scanFlags) error {
+	cfg := sf.cfg
+	opts := sf.opts
+
+	if cfg.Concurrency <= 0 {
+		return fmt.Errorf("-c must be greater than zero")
+	}
+	if sf.timeoutSec <= 0 {
+		return fmt.Errorf("-t must be greater than zero")
+	}
+	if cfg.Retries <= 0 {
+		return fmt.Errorf("--retries must be greater than zero")
+	}
+	if strings.TrimSpace(cfg.Method) == "" {
+		return fmt.Errorf("-X must not be empty")
+	}
+	for _, h := range sf.headers {
+		if !strings.Contains(h, ":") {
+			return fmt.Errorf("invalid header %q: expected \"Key: Value\"", h)
+		}
+	}
+	if sf.data != "" && sf.dataFile != "" {
+		return fmt.Errorf("--data and --data-file cannot be combined")
+	}
+	if sf.dataFile != "" && !utils.PathExists(sf.dataFile) {
+		return fmt.Errorf("data file not found: %s", sf.dataFile)
+	}
+	for _, source := range splitList(sf.imports) {
+		if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") && !utils.PathExists(source) {
+			return fmt.Errorf("import file not found: %s", source)
+		}
+	}
+	if sf.importBase != "" {
+		parsed, err := url.Parse(sf.importBase)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("invalid --import-base %q: expected scheme://host", sf.importBase)
+		}
+	}
+	for _, format := range splitList(strings.ToLower(sf.formats)) {
+		if !output.IsFormat(format) {
+			return fmt.Errorf("unknown --format %q (want %s)", format, strings.Join(output.Formats, ", "))
+		}
+	}
+	cfg.MinSeverity = strings.ToLower(strings.TrimSpace(cfg.MinSeverity))
+	if cfg.MinSeverity != "" && !common.IsSeverity(cfg.MinSeverity) {
+		return fmt.Errorf("unknown --min-severity %q (want info, low, medium, high or critical)", cfg.MinSeverity)
+	}
+	if sf.formats != "" && cfg.OutputFile == "" {
+		return fmt.Errorf("--format requires -o")
+	}
+	if sf.requestFile != "" && !utils.PathExists(sf.requestFile) {
+		return fmt.Errorf("request file not found: %s", sf.requestFile)
+	}
+	if err := sf.auth.validate(); err != nil {
+		return err
+	}
+	if err := sf.scope.validate(); err != nil {
+		return err
+	}
+
+	if c.groups&groupBypass != 0 {
+		for _, tech := range strings.Split(opts.techniques, ",") {
+			tech = strings.TrimSpace(tech)
+			if !bypass.IsTechnique(tech) {
+				return fmt.Errorf("unknown technique %q (valid: %s)", tech, defaultTechniques)
+			}
+		}
+		if opts.chainTop <= 0 {
+			return fmt.Errorf("--chain-top must be greater than zero")
+		}
+		if opts.chainBudget < 0 {
+			return fmt.Errorf("--chain-budget must not be negative")
+		}
+		if !containsString(bypass.TargetForms, opts.targetForm) {
+			return fmt.Errorf("invalid --target-form %q (want %s)", opts.targetForm, strings.Join(bypass.TargetForms, " or "))
+		}
+	}
+	if c.groups&groupCORS != 0 && opts.origin != "null" {
+		parsed, err := url.Parse(opts.origin)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("invalid --origin %q: expected scheme://host", opts.origin)
+		}
+	}
+	if c.groups&groupMethods != 0 && opts.filterStatus != "" {
+		for _, code := range strings.Split(opts.filterStatus, ",") {
+			if _, err := strconv.Atoi(strings.TrimSpace(code)); err != nil {
+				return fmt.Errorf("invalid status code %q in --status", code)
+			}
+		}
+	}
+	if c.groups&groupSmuggle != 0 {
+		if opts.interval <= 0 {
+			return fmt.Errorf("--interval must be greater than zero")
+		}
+		if opts.gadgetFile != "" && !utils.PathExists(opts.gadgetFile) {
+			return fmt.Errorf("gadget file not found: %s", opts.gadgetFile)
+		}
+	}
+
+	if c.groups&groupVerify != 0 {
+		if opts.input == "" {
+			return fmt.Errorf("--input is required")
+		}
+		if !utils.PathExists(opts.input) {
+			return fmt.Errorf("input file not found: %s", opts.input)
+		}
+		if opts.replays <= 0 {
+			return fmt.Errorf("--replays must be greater than zero")
+		}
+	}
+
+	if c.groups&groupAll != 0 {
+		for _, list := range []string{opts.modules, opts.skip} {
+			for _, module := range splitList(list) {
+				if !containsString(scanModules, module) {
+					return fmt.Errorf("unknown module %q (valid: %s)", module, strings.Join(scanModules, ","))
+				}
+			}
+		}
+		if len(selectedModules(opts)) == 0 {
+			return fmt.Errorf("no modules left to run after --skip")
+		}
+	}
+
+	return nil
+}
+
+// selectedModules returns the modules chosen with --modules minus --skip, in execution order
+func selectedModules(opts *scanOptions) []string {
+	wanted := splitList(opts.modules)
+	skipped := splitList(opts.skip)
+
+	selected := make([]string, 0, len(scanModules))
+	for _, module := range scanModules {
+		if containsString(wanted, module) && !containsString(skipped, module) {
+			selected = append(selected, module)
+		}
+	}
+	return selected
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
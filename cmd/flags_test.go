@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/output"
+)
+
+func TestParseGlobalFlagsRejectsModuleFlagsOfOtherCommands(t *testing.T) {
+	if _, _, err := parseGlobalFlags([]string{"-u", "example.com", "--deep"}, "crlf"); err == nil {
+		t.Fatalf("expected crlf to reject the cors --deep flag")
+	}
+}
+
+func TestParseGlobalFlagsRejectsUnknownTechnique(t *testing.T) {
+	_, _, err := parseGlobalFlags([]string{"-u", "example.com", "--techniques", "headers,bogus"}, "bypass")
+	if err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Fatalf("expected unknown technique error, got %v", err)
+	}
+}
+
+func TestParseGlobalFlagsRejectsInvalidValues(t *testing.T) {
+	for _, tc := range []struct {
+		command string
+		args    []string
+	}{
+		{"cors", []string{"--origin", "evil"}},
+		{"methods", []string{"--status", "200,abc"}},
+		{"smuggle", []string{"--interval", "0"}},
+		{"crlf", []string{"-c", "0"}},
+		{"crlf", []string{"-H", "missing-colon"}},
+		{"crlf", []string{"--format", "sarif"}},
+		{"crlf", []string{"--min-severity", "severe"}},
+		{"crlf", []string{"-o", "out", "--format", "json,pdf"}},
+		{"verify", nil},
+		{"verify", []string{"--input", "missing.json"}},
+		{"verify", []string{"--input", "flags_test.go", "--replays", "0"}},
+	} {
+		if _, _, err := parseGlobalFlags(append([]string{"-u", "example.com"}, tc.args...), tc.command); err == nil {
+			t.Fatalf("expected %s %v to be rejected", tc.command, tc.args)
+		}
+	}
+}
+
+func TestParseGlobalFlagsFormats(t *testing.T) {
+	cfg, _, err := parseGlobalFlags([]string{"-u", "example.com", "-o", "scan.json", "--format", "SARIF,html,sarif"}, "cors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(cfg.Formats, ",") != "sarif,html" {
+		t.Fatalf("unexpected formats: %v", cfg.Formats)
+	}
+}
+
+func TestCompletionScriptsListCommandFlags(t *testing.T) {
+	var bash, zsh, fish bytes.Buffer
+	writeBashCompletion(&bash)
+	writeZshCompletion(&zsh)
+	writeFishCompletion(&fish)
+
+	for shell, script := range map[string]string{
+		"bash": bash.String(),
+		"zsh":  zsh.String(),
+		"fish": fish.String(),
+	} {
+		for _, want := range []string{"bypass", "techniques", "midpaths"} {
+			if !strings.Contains(script, want) {
+				t.Fatalf("expected %s completion to mention %q", shell, want)
+			}
+		}
+	}
+
+	if !strings.Contains(fish.String(), "'__fish_seen_subcommand_from cors' -l deep") {
+		t.Fatalf("expected fish completion for cors --deep")
+	}
+	if strings.Contains(fish.String(), "'__fish_seen_subcommand_from crlf' -l deep") {
+		t.Fatalf("unexpected cors flag in crlf completion")
+	}
+}
+
+func TestParseGlobalFlagsAllAcceptsModuleFlags(t *testing.T) {
+	_, opts, err := parseGlobalFlags([]string{
+		"-u", "example.com",
+		"--origin", "https://attacker.com",
+		"--techniques", "headers",
+		"--status", "200",
+		"--modules", "bypass,cors,methods",
+		"--skip", "methods",
+	}, "all")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+
+	if opts.origin != "https://attacker.com" || opts.techniques != "headers" || opts.filterStatus != "200" {
+		t.Fatalf("module flags not applied: %+v", opts)
+	}
+	if got := strings.Join(selectedModules(opts), ","); got != "bypass,cors" {
+		t.Fatalf("unexpected module selection: %q", got)
+	}
+}
+
+func TestParseGlobalFlagsAllRejectsBadModuleSelection(t *testing.T) {
+	for _, args := range [][]string{
+		{"--modules", "bypass,xss"},
+		{"--skip", "nope"},
+		{"--modules", "cors", "--skip", "cors"},
+	} {
+		if _, _, err := parseGlobalFlags(append([]string{"-u", "example.com"}, args...), "all"); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseGlobalFlagsUsesRequestFileAsBaseRequest(t *testing.T) {
+	path := writeConfigFile(t, "request.txt", "POST /api/login HTTP/1.1\r\n"+
+		"Host: api.example.com\r\n"+
+		"Content-Type: application/json\r\n"+
+		"X-Api-Key: from-file\r\n"+
+		"Access-Control-Request-Method: POST\r\n"+
+		"\r\n"+
+		`{"user":"a"}`)
+
+	cfg, _, err := parseGlobalFlags([]string{"-r", path, "-H", "X-Api-Key: from-cli"}, "bypass")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+
+	if cfg.Method != "POST" || string(cfg.Body) != `{"user":"a"}` || cfg.ContentType != "application/json" {
+		t.Fatalf("base request not applied: method=%s body=%q type=%q", cfg.Method, cfg.Body, cfg.ContentType)
+	}
+	if len(cfg.URLs) != 1 || cfg.URLs[0] != "https://api.example.com/api/login" {
+		t.Fatalf("unexpected targets: %v", cfg.URLs)
+	}
+	if cfg.Headers["X-Api-Key"] != "from-cli" {
+		t.Fatalf("expected -H to override the request file, got %q", cfg.Headers["X-Api-Key"])
+	}
+	if _, ok := cfg.Headers["Access-Control-Request-Method"]; ok {
+		t.Fatal("expected preflight headers of the request file to be dropped")
+	}
+
+	cfg, _, err = parseGlobalFlags([]string{"-r", path, "-X", "put", "-u", "https://staging.example.com/api/login"}, "cors")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if cfg.Method != "PUT" || cfg.URLs[0] != "https://staging.example.com/api/login" {
+		t.Fatalf("expected -X and -u to win, got %s %v", cfg.Method, cfg.URLs)
+	}
+}
+
+func TestParseGlobalFlagsRequestBody(t *testing.T) {
+	cfg, _, err := parseGlobalFlags([]string{"-u", "example.com/api", "--data", `{"id":1}`}, "methods")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if cfg.Method != "POST" || string(cfg.Body) != `{"id":1}` || cfg.ContentType != "application/json" {
+		t.Fatalf("unexpected body settings: method=%s body=%q type=%q", cfg.Method, cfg.Body, cfg.ContentType)
+	}
+
+	path := writeConfigFile(t, "body.xml", "<id>1</id>")
+	cfg, _, err = parseGlobalFlags([]string{"-u", "example.com/api", "-X", "PUT", "--data-file", path, "--content-type", "text/xml"}, "bypass")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if cfg.Method != "PUT" || string(cfg.Body) != "<id>1</id>" || cfg.ContentType != "text/xml" {
+		t.Fatalf("unexpected body settings: method=%s body=%q type=%q", cfg.Method, cfg.Body, cfg.ContentType)
+	}
+
+	if _, _, err := parseGlobalFlags([]string{"-u", "example.com", "--data", "a", "--data-file", path}, "cors"); err == nil {
+		t.Fatalf("expected --data and --data-file to be rejected together")
+	}
+}
+
+func TestParseGlobalFlagsImportsTargets(t *testing.T) {
+	path := writeConfigFile(t, "sitemap.xml", `<?xml version="1.0"?>
+<urlset><url><loc>https://example.com/a</loc></url><url><loc>https://example.com/b</loc></url></urlset>`)
+
+	cfg, _, err := parseGlobalFlags([]string{"-u", "https://example.com/admin", "--import", path}, "bypass")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if len(cfg.Targets) != 3 || cfg.Targets[0].URL != "https://example.com/admin" || cfg.Targets[2].URL != "https://example.com/b" {
+		t.Fatalf("unexpected targets: %+v", cfg.Targets)
+	}
+	if len(cfg.URLs) != 3 {
+		t.Fatalf("expected imported URLs in cfg.URLs, got %v", cfg.URLs)
+	}
+
+	if _, _, err := parseGlobalFlags([]string{"--import", "missing.har"}, "cors"); err == nil {
+		t.Fatalf("expected a missing import file to be rejected")
+	}
+}
+
+func TestStartScopeDropsOutOfScopeTargets(t *testing.T) {
+	rules := writeConfigFile(t, "scope.txt", "# program scope\n*.example.com\n")
+	cfg, _, err := parseGlobalFlags([]string{
+		"-u", "https://app.example.com/admin",
+		"--import", writeConfigFile(t, "sitemap.xml", `<urlset><url><loc>https://cdn.other.net/x</loc></url></urlset>`),
+		"--scope-file", rules,
+		"--out-of-scope", "path:^/logout",
+	}, "bypass")
+	if err != nil {
+		t.Fatalf("parseGlobalFlags returned error: %v", err)
+	}
+	if cfg.Scope == nil {
+		t.Fatalf("expected scope rules to be built")
+	}
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	if err := startScope(cfg, printer); err != nil {
+		t.Fatalf("startScope returned error: %v", err)
+	}
+	if len(cfg.URLs) != 1 || len(cfg.Targets) != 1 || cfg.Targets[0].URL != "https://app.example.com/admin" {
+		t.Fatalf("expected only the in-scope target to remain, got %v", cfg.URLs)
+	}
+
+	if _, _, err := parseGlobalFlags([]string{"-u", "https://example.com", "--scope", "path:("}, "cors"); err == nil {
+		t.Fatalf("expected an invalid scope rule to be rejected")
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/preflight"
+)
+
+// runPreflight checks every target before the scan, prints what it learned
+// per host and drops targets on dead or parked hosts. Modules read the
+// cached results from cfg.Preflight.
+func runPreflight(cfg *config.Config, printer *output.Printer) error {
+	if !cfg.PreflightEnabled {
+		return nil
+	}
+	printer.SectionHeader("PRE-FLIGHT")
+
+	client := httpclient.New(httpclient.Options{
+		Timeout:     cfg.Timeout,
+		Proxy:       cfg.Proxy,
+		UserAgent:   cfg.UserAgent,
+		Headers:     cfg.Headers,
+		Retries:     cfg.Retries,
+		Redirect:    cfg.Redirect,
+		Insecure:    true,
+		Session:     cfg.Session,
+		Scope:       cfg.Scope,
+		ContentType: cfg.ContentType,
+	})
+	results := preflight.New(client)
+
+	targets := cfg.ScanTargets()
+	progress := printer.StartProgress("preflight")
+	progress.AddTotal(len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, cfg.Concurrency)
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target common.Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info := results.Check(target.Method, target.URL, target.RequestHeaders(), target.Body)
+			if info.Alive {
+				progress.Request(nil)
+			} else {
+				progress.Request(fmt.Errorf("%s", info.Error))
+			}
+		}(target)
+	}
+	wg.Wait()
+	progress.Finish()
+
+	for _, info := range results.Hosts() {
+		printer.Preflight(info)
+	}
+	cfg.Preflight = results
+
+	usable := func(targetURL string) bool {
+		info, ok := results.Host(targetURL)
+		return ok && info.Alive && !info.Parked
+	}
+	before := len(cfg.URLs)
+	urls := cfg.URLs[:0]
+	for _, u := range cfg.URLs {
+		if usable(u) {
+			urls = append(urls, u)
+		}
+	}
+	cfg.URLs = urls
+	kept := cfg.Targets[:0]
+	for _, t := range cfg.Targets {
+		if usable(t.URL) {
+			kept = append(kept, t)
+		}
+	}
+	cfg.Targets = kept
+
+	if dropped := before - len(cfg.URLs); dropped > 0 {
+		printer.Warning("Dropped %d target(s) on dead or parked hosts", dropped)
+	}
+	if len(cfg.URLs) == 0 {
+		printer.Error("No live targets left after pre-flight")
+		return fmt.Errorf("no live targets")
+	}
+	return nil
+}
@@ -1,10 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -15,9 +14,9 @@ import (
 	"github.com/aether-0/httpsuite/internal/methods"
 	"github.com/aether-0/httpsuite/internal/smuggle"
 	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
 	"github.com/aether-0/httpsuite/pkg/output"
 	"github.com/aether-0/httpsuite/pkg/payloadsync"
-	"github.com/aether-0/httpsuite/pkg/utils"
 )
 
 // Execute parses CLI arguments and runs the appropriate module
@@ -30,31 +29,25 @@ func Execute() error {
 	subcommand := os.Args[1]
 
 	switch subcommand {
-	case "bypass":
-		return runBypass(os.Args[2:])
-	case "crlf":
-		return runCRLF(os.Args[2:])
-	case "cors":
-		return runCORS(os.Args[2:])
-	case "methods":
-		return runMethods(os.Args[2:])
-	case "smuggle":
-		return runSmuggle(os.Args[2:])
-	case "all":
-		return runAll(os.Args[2:])
-	case "sync-payloads":
-		return runSyncPayloads(os.Args[2:])
 	case "help", "-h", "--help":
-		printUsage()
-		return nil
+		return runHelp(os.Args[2:])
 	case "version", "--version":
-		fmt.Println("httpsuite v1.0.0")
+		fmt.Println("httpsuite v" + common.Version)
 		return nil
-	default:
+	}
+
+	c := lookupCommand(subcommand)
+	if c == nil {
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", subcommand)
 		printUsage()
 		return fmt.Errorf("unknown command: %s", subcommand)
 	}
+
+	err := c.run(os.Args[2:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
 }
 
 func printUsage() {
@@ -71,8 +64,8 @@ func printUsage() {
 	fmt.Printf("|  _  | | |   | | |  __/ ___) | |_| || |  | | | |___ \n")
 	fmt.Printf("|_| |_| |_|   |_| |_|   |____/ \\___/|___| |_| |_____|%s\n", reset)
 	fmt.Printf("%s\n", reset)
-	fmt.Print(`  httpsuite v1.0
-  Smart HTTP Security Testing for Pentest and Bug Bounty Work
+	fmt.Printf("  httpsuite v%s\n", common.Version)
+	fmt.Print(`  Smart HTTP Security Testing for Pentest and Bug Bounty Work
   Bypass • CRLF • CORS • Methods • Smuggle • Sync
 
 Usage:
@@ -85,23 +78,48 @@ Available Commands:
   methods     Test allowed HTTP methods on targets (inspired by httpc)
   smuggle     Test for HTTP request smuggling via H2 downgrade (inspired by smugglefuzz)
   all         Run all modules against target(s)
+  verify      Replay bypass findings from a JSON output and report which still reproduce
   sync-payloads  Download current upstream payload files into a local payload directory
-  help        Show this help message
+  completion  Generate shell completion for bash, zsh, or fish
+  help        Show this help message, or 'help <command>' for command flags
   version     Show version
 
-Global Flags (available for all commands):
+Run 'httpsuite <command> -h' for module-specific flags.
+
+Global Flags (available for all scan commands):
   -u  string    Target URL
   -l  string    File containing list of URLs (one per line)
+  -r  string    Raw HTTP request file (e.g. saved from Burp) used as the base request
+  --import string       HAR, OpenAPI/Swagger or sitemap.xml files/URLs to import targets from
+  -X  string    HTTP method for the base request (default: GET)
+  --data string         Request body (implies POST unless -X is set)
+  --data-file string    File containing the request body
+  --content-type string Content-Type of the body (guessed when empty)
   -c  int       Concurrency level (default: 10)
   -t  int       Timeout in seconds (default: 10)
+  --retries int Attempts per request (default: 1)
   -x  string    Proxy URL (e.g., http://127.0.0.1:8080)
   -H  string    Custom header (Key: Value) — can be repeated
   -o  string    Output file path
   -j            JSON output mode
+  --format string   Report formats for -o: text, json, jsonl, sarif, markdown, html (comma-separated)
+  --min-severity string  Only report results at or above info, low, medium, high or critical
+  --unique      Cluster results with the same target, status and fingerprint
   -s            Silent mode
   -v            Verbose mode
   --payload-dir string  Local payload override directory (default: payloads)
   --no-color    Disable colored output
+  --no-progress Disable the live progress line on stderr
+  --config string   YAML or TOML configuration file (CLI flags override it)
+  --profile string  Named profile from the config file (built-in: stealth, aggressive)
+  --cookie string       Raw Cookie header value sent with every request
+  --cookie-file string  Netscape cookies.txt file to load
+  --login-url string    Scripted login run before scanning (see --login-body, --login-success)
+  --logged-out string   Matcher for logged-out responses; triggers re-login
+  --token-url string    Token endpoint for bearer authentication
+  --preflight   Check liveness, TLS, h2, WAF and wildcard 404s per host before scanning
+  --scope string        In-scope rules: host globs, IPs/CIDRs, path:<regex> (see --scope-file)
+  --out-of-scope string Out-of-scope rules; always win (see --out-of-scope-file)
 
 Examples:
   httpsuite bypass -u https://example.com/admin
@@ -110,138 +128,46 @@ Examples:
   httpsuite methods -u https://example.com
   httpsuite smuggle -u https://example.com
   httpsuite all -u https://example.com
+  httpsuite all -l crawl.txt --per-host
+  httpsuite all -l hosts.txt --preflight -j
+  httpsuite bypass -r request.txt
+  httpsuite methods --import openapi.yaml --import-base https://api.example.com
+  httpsuite bypass -l hosts.txt --scope '*.example.com' --out-of-scope 'path:^/logout'
+  httpsuite bypass --config team.yaml --profile stealth -u https://example.com/admin
+  httpsuite verify --input scan.jsonl --replays 5
   httpsuite sync-payloads
   cat urls.txt | httpsuite crlf
 
 `)
 }
 
-// multiFlag allows repeating -H flags
-type multiFlag []string
-
-func (m *multiFlag) String() string { return strings.Join(*m, ", ") }
-func (m *multiFlag) Set(val string) error {
-	*m = append(*m, val)
-	return nil
-}
-
-// parseGlobalFlags parses global flags shared by all subcommands
-func parseGlobalFlags(args []string, name string) (*common.Config, error) {
-	cfg := common.DefaultConfig()
-	fs := flag.NewFlagSet(name, flag.ContinueOnError)
-
-	var headers multiFlag
-	var proxyStr string
-	var timeoutSec int
-	var listFile string
-
-	fs.StringVar(&cfg.URL, "u", "", "Target URL")
-	fs.StringVar(&listFile, "l", "", "File containing list of URLs")
-	fs.IntVar(&cfg.Concurrency, "c", 10, "Concurrency level")
-	fs.IntVar(&timeoutSec, "t", 10, "Timeout in seconds")
-	fs.StringVar(&proxyStr, "x", "", "Proxy URL")
-	fs.Var(&headers, "H", "Custom header (Key: Value)")
-	fs.StringVar(&cfg.OutputFile, "o", "", "Output file")
-	fs.BoolVar(&cfg.JSONOutput, "j", false, "JSON output")
-	fs.BoolVar(&cfg.Silent, "s", false, "Silent mode")
-	fs.BoolVar(&cfg.Verbose, "v", false, "Verbose mode")
-	fs.BoolVar(&cfg.NoColor, "no-color", false, "Disable color")
-	fs.BoolVar(&cfg.Redirect, "redirect", false, "Follow redirects")
-	fs.StringVar(&cfg.PayloadDir, "payload-dir", cfg.PayloadDir, "Local payload override directory")
-	fs.StringVar(&cfg.UserAgent, "ua", "httpsuite/1.0", "User-Agent string")
-	fs.BoolVar(&cfg.RandomAgent, "random-agent", false, "Use random User-Agent")
-
-	// Module-specific flags (ignored if not relevant)
-	var techniques, bypassIP, origin, methodList, filterStatus, gadgetFile string
-	var deepScan, extended bool
-	var smuggleTimeout int
-	fs.StringVar(&techniques, "techniques", "headers,endpaths,midpaths,verbs,verbs-case,double-encoding,http-versions,path-case", "Bypass techniques")
-	fs.StringVar(&bypassIP, "bypass-ip", "", "Custom IP for header-based bypass")
-	fs.StringVar(&origin, "origin", "https://evil.com", "Custom origin for CORS testing")
-	fs.BoolVar(&deepScan, "deep", false, "Enable deep CORS scan")
-	fs.StringVar(&methodList, "methods", "", "Comma-separated HTTP methods")
-	fs.StringVar(&filterStatus, "status", "", "Filter by status codes")
-	fs.BoolVar(&extended, "extended", false, "Use extended gadget list")
-	fs.StringVar(&gadgetFile, "wordlist", "", "Custom gadget/payload file")
-	fs.IntVar(&smuggleTimeout, "interval", 5, "Detection timeout in seconds")
-
-	if err := fs.Parse(args); err != nil {
-		return nil, err
-	}
-
-	cfg.Timeout = time.Duration(timeoutSec) * time.Second
-
-	if proxyStr != "" {
-		cfg.ProxyStr = proxyStr
-		p, err := url.Parse(proxyStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy URL: %w", err)
-		}
-		cfg.Proxy = p
-	}
-
-	// Parse custom headers
-	for _, h := range headers {
-		parts := strings.SplitN(h, ":", 2)
-		if len(parts) == 2 {
-			cfg.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-		}
-	}
-
-	// Collect URLs
-	if cfg.URL != "" {
-		cfg.URLs = append(cfg.URLs, utils.NormalizeURL(cfg.URL))
+// newPrinter creates the result printer for a scan command
+func newPrinter(cfg *config.Config) *output.Printer {
+	outputFile := cfg.OutputFile
+	if len(cfg.Formats) > 0 {
+		outputFile = ""
 	}
-
-	if listFile != "" {
-		lines, err := utils.ReadLines(listFile)
-		if err != nil {
-			return nil, fmt.Errorf("error reading URL list: %w", err)
+	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, outputFile)
+	printer.SetMinSeverity(cfg.MinSeverity)
+	printer.SetUnique(cfg.Unique)
+	for _, format := range cfg.Formats {
+		path := cfg.OutputFile
+		if len(cfg.Formats) > 1 {
+			path = output.FormatPath(cfg.OutputFile, format)
 		}
-		for _, line := range lines {
-			cfg.URLs = append(cfg.URLs, utils.NormalizeURL(line))
+		if err := printer.AddOutput(format, path); err != nil {
+			printer.Error("Cannot write %s output: %v", format, err)
 		}
 	}
-
-	if utils.HasStdin() && len(cfg.URLs) == 0 {
-		cfg.URLs = utils.ReadURLsFromStdin()
-	}
-
-	if cfg.RandomAgent {
-		cfg.UserAgent = utils.RandomUserAgent()
+	if cfg.NoProgress {
+		printer.DisableProgress()
 	}
-
-	return cfg, nil
-}
-
-// helper to get a string flag value by name from args (silently ignores unknown flags)
-func getFlagStr(args []string, name, defaultVal string) string {
-	fs := flag.NewFlagSet("temp", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
-	val := fs.String(name, defaultVal, "")
-	fs.Parse(args)
-	return *val
-}
-
-func getFlagBool(args []string, name string) bool {
-	fs := flag.NewFlagSet("temp", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
-	val := fs.Bool(name, false, "")
-	fs.Parse(args)
-	return *val
-}
-
-func getFlagInt(args []string, name string, defaultVal int) int {
-	fs := flag.NewFlagSet("temp", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
-	val := fs.Int(name, defaultVal, "")
-	fs.Parse(args)
-	return *val
+	return printer
 }
 
 // runBypass handles the bypass subcommand
 func runBypass(args []string) error {
-	cfg, err := parseGlobalFlags(args, "bypass")
+	cfg, opts, err := parseGlobalFlags(args, "bypass")
 	if err != nil {
 		return err
 	}
@@ -251,24 +177,49 @@ func runBypass(args []string) error {
 		return fmt.Errorf("no targets specified")
 	}
 
-	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, cfg.OutputFile)
+	printer := newPrinter(cfg)
 	defer printer.Close()
 	printer.Banner()
 
-	techniques := getFlagStr(args, "techniques", "headers,endpaths,midpaths,verbs,verbs-case,double-encoding,http-versions,path-case")
-	bypassIP := getFlagStr(args, "bypass-ip", "")
-	techs := strings.Split(techniques, ",")
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	prepareTargets(cfg, printer)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+	if err := runPreflight(cfg, printer); err != nil {
+		return err
+	}
 
-	for _, targetURL := range cfg.URLs {
-		scanner := bypass.NewScanner(cfg, printer, targetURL, techs, bypassIP)
-		scanner.Run()
+	warnRawProxy(cfg, printer)
+	// Targets on the same origin share calibration and successful tricks
+	hosts := bypass.NewHosts()
+	for _, target := range cfg.ScanTargets() {
+		newBypassScanner(cfg, printer, target, opts, hosts).Run()
 	}
 	return nil
 }
 
+// newBypassScanner applies the bypass flags to a scanner for one target
+func newBypassScanner(cfg *config.Config, printer *output.Printer, target common.Target, opts *scanOptions, hosts *bypass.Hosts) *bypass.Scanner {
+	scanner := bypass.NewScanner(cfg, printer, target, strings.Split(opts.techniques, ","), opts.bypassIP)
+	scanner.SetChainLimits(opts.chainTop, opts.chainBudget)
+	scanner.SetTargetForm(opts.targetForm)
+	scanner.SetHosts(hosts)
+	return scanner
+}
+
+// warnRawProxy explains that path payloads lose their raw form through a proxy
+func warnRawProxy(cfg *config.Config, printer *output.Printer) {
+	if cfg.Proxy != nil {
+		printer.Warning("Path payloads are sent through the proxy with Go's URL handling and may be normalized; request-target forms are skipped")
+	}
+}
+
 // runCRLF handles the crlf subcommand
 func runCRLF(args []string) error {
-	cfg, err := parseGlobalFlags(args, "crlf")
+	cfg, _, err := parseGlobalFlags(args, "crlf")
 	if err != nil {
 		return err
 	}
@@ -278,10 +229,21 @@ func runCRLF(args []string) error {
 		return fmt.Errorf("no targets specified")
 	}
 
-	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, cfg.OutputFile)
+	printer := newPrinter(cfg)
 	defer printer.Close()
 	printer.Banner()
 
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	prepareTargets(cfg, printer)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+	if err := runPreflight(cfg, printer); err != nil {
+		return err
+	}
+
 	scanner := crlf.NewScanner(cfg, printer)
 	scanner.Run()
 	return nil
@@ -289,7 +251,7 @@ func runCRLF(args []string) error {
 
 // runCORS handles the cors subcommand
 func runCORS(args []string) error {
-	cfg, err := parseGlobalFlags(args, "cors")
+	cfg, opts, err := parseGlobalFlags(args, "cors")
 	if err != nil {
 		return err
 	}
@@ -299,21 +261,32 @@ func runCORS(args []string) error {
 		return fmt.Errorf("no targets specified")
 	}
 
-	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, cfg.OutputFile)
+	printer := newPrinter(cfg)
 	defer printer.Close()
 	printer.Banner()
 
-	origin := getFlagStr(args, "origin", "https://evil.com")
-	deepScan := getFlagBool(args, "deep")
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	prepareTargets(cfg, printer)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+	if err := runPreflight(cfg, printer); err != nil {
+		return err
+	}
 
-	scanner := cors.NewScanner(cfg, printer, origin, deepScan)
+	if opts.perHost {
+		cfg = perHostConfig(cfg)
+	}
+	scanner := cors.NewScanner(cfg, printer, opts.origin, opts.deepScan)
 	scanner.Run()
 	return nil
 }
 
 // runMethods handles the methods subcommand
 func runMethods(args []string) error {
-	cfg, err := parseGlobalFlags(args, "methods")
+	cfg, opts, err := parseGlobalFlags(args, "methods")
 	if err != nil {
 		return err
 	}
@@ -323,21 +296,29 @@ func runMethods(args []string) error {
 		return fmt.Errorf("no targets specified")
 	}
 
-	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, cfg.OutputFile)
+	printer := newPrinter(cfg)
 	defer printer.Close()
 	printer.Banner()
 
-	methodList := getFlagStr(args, "methods", "")
-	filterStatus := getFlagStr(args, "status", "")
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	prepareTargets(cfg, printer)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+	if err := runPreflight(cfg, printer); err != nil {
+		return err
+	}
 
-	scanner := methods.NewScanner(cfg, printer, methodList, filterStatus)
+	scanner := methods.NewScanner(cfg, printer, opts.methodList, opts.filterStatus)
 	scanner.Run()
 	return nil
 }
 
 // runSmuggle handles the smuggle subcommand
 func runSmuggle(args []string) error {
-	cfg, err := parseGlobalFlags(args, "smuggle")
+	cfg, opts, err := parseGlobalFlags(args, "smuggle")
 	if err != nil {
 		return err
 	}
@@ -347,22 +328,32 @@ func runSmuggle(args []string) error {
 		return fmt.Errorf("no targets specified")
 	}
 
-	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, cfg.OutputFile)
+	printer := newPrinter(cfg)
 	defer printer.Close()
 	printer.Banner()
 
-	ext := getFlagBool(args, "extended")
-	gadgetFile := getFlagStr(args, "wordlist", "")
-	interval := getFlagInt(args, "interval", 5)
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	prepareTargets(cfg, printer)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+	if err := runPreflight(cfg, printer); err != nil {
+		return err
+	}
 
-	scanner := smuggle.NewScanner(cfg, printer, ext, gadgetFile, interval)
+	if opts.perHost {
+		cfg = perHostConfig(cfg)
+	}
+	scanner := smuggle.NewScanner(cfg, printer, opts.extended, opts.gadgetFile, opts.interval)
 	scanner.Run()
 	return nil
 }
 
-// runAll runs all modules against the target(s)
+// runAll runs the selected modules against the target(s)
 func runAll(args []string) error {
-	cfg, err := parseGlobalFlags(args, "all")
+	cfg, opts, err := parseGlobalFlags(args, "all")
 	if err != nil {
 		return err
 	}
@@ -372,37 +363,52 @@ func runAll(args []string) error {
 		return fmt.Errorf("no targets specified")
 	}
 
-	printer := output.NewPrinter(cfg.Silent, cfg.NoColor, cfg.JSONOutput, cfg.OutputFile)
+	printer := newPrinter(cfg)
 	defer printer.Close()
 	printer.Banner()
 
-	// Run bypass
-	printer.SectionHeader("403 BYPASS SCAN")
-	for _, targetURL := range cfg.URLs {
-		techs := []string{"headers", "endpaths", "midpaths", "verbs", "verbs-case", "double-encoding", "http-versions", "path-case"}
-		scanner := bypass.NewScanner(cfg, printer, targetURL, techs, "")
-		scanner.Run()
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	prepareTargets(cfg, printer)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+	if err := runPreflight(cfg, printer); err != nil {
+		return err
 	}
 
-	// Run CRLF
-	printer.SectionHeader("CRLF INJECTION SCAN")
-	crlfScanner := crlf.NewScanner(cfg, printer)
-	crlfScanner.Run()
-
-	// Run CORS
-	printer.SectionHeader("CORS MISCONFIGURATION SCAN")
-	corsScanner := cors.NewScanner(cfg, printer, "https://evil.com", false)
-	corsScanner.Run()
+	selected := selectedModules(opts)
+	printer.Info("Running modules: %s", strings.Join(selected, ", "))
 
-	// Run Methods
-	printer.SectionHeader("HTTP METHOD SCAN")
-	methodsScanner := methods.NewScanner(cfg, printer, "", "")
-	methodsScanner.Run()
+	hostCfg := cfg
+	if opts.perHost {
+		hostCfg = perHostConfig(cfg)
+	}
 
-	// Run Smuggle
-	printer.SectionHeader("HTTP SMUGGLING SCAN")
-	smuggleScanner := smuggle.NewScanner(cfg, printer, false, "", 5)
-	smuggleScanner.Run()
+	for _, module := range selected {
+		switch module {
+		case "bypass":
+			printer.SectionHeader("403 BYPASS SCAN")
+			warnRawProxy(cfg, printer)
+			hosts := bypass.NewHosts()
+			for _, target := range cfg.ScanTargets() {
+				newBypassScanner(cfg, printer, target, opts, hosts).Run()
+			}
+		case "crlf":
+			printer.SectionHeader("CRLF INJECTION SCAN")
+			crlf.NewScanner(cfg, printer).Run()
+		case "cors":
+			printer.SectionHeader("CORS MISCONFIGURATION SCAN")
+			cors.NewScanner(hostCfg, printer, opts.origin, opts.deepScan).Run()
+		case "methods":
+			printer.SectionHeader("HTTP METHOD SCAN")
+			methods.NewScanner(cfg, printer, opts.methodList, opts.filterStatus).Run()
+		case "smuggle":
+			printer.SectionHeader("HTTP SMUGGLING SCAN")
+			smuggle.NewScanner(hostCfg, printer, opts.extended, opts.gadgetFile, opts.interval).Run()
+		}
+	}
 
 	// Summary
 	totalResults, vulnCount := printer.Stats()
@@ -411,29 +417,43 @@ func runAll(args []string) error {
 	return nil
 }
 
-func runSyncPayloads(args []string) error {
-	fs := flag.NewFlagSet("sync-payloads", flag.ContinueOnError)
+// syncFlags holds the flag values of the sync-payloads command
+type syncFlags struct {
+	payloadDir string
+	timeoutSec int
+	silent     bool
+	noColor    bool
+}
+
+func newSyncFlagSet(c *command) (*flag.FlagSet, *syncFlags) {
+	sf := &syncFlags{}
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() { printCommandUsage(fs, c) }
 
-	payloadDir := "payloads"
-	timeoutSec := 20
-	silent := false
-	noColor := false
+	fs.StringVar(&sf.payloadDir, "payload-dir", "payloads", "Local payload override directory")
+	fs.IntVar(&sf.timeoutSec, "t", 20, "Timeout in seconds")
+	fs.BoolVar(&sf.silent, "s", false, "Silent mode")
+	fs.BoolVar(&sf.noColor, "no-color", false, "Disable color")
 
-	fs.StringVar(&payloadDir, "payload-dir", payloadDir, "Local payload override directory")
-	fs.IntVar(&timeoutSec, "t", timeoutSec, "Timeout in seconds")
-	fs.BoolVar(&silent, "s", false, "Silent mode")
-	fs.BoolVar(&noColor, "no-color", false, "Disable color")
+	return fs, sf
+}
 
+func runSyncPayloads(args []string) error {
+	fs, sf := newSyncFlagSet(lookupCommand("sync-payloads"))
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if sf.timeoutSec <= 0 {
+		return fmt.Errorf("-t must be greater than zero")
+	}
 
-	printer := output.NewPrinter(silent, noColor, false, "")
+	payloadDir := sf.payloadDir
+	printer := output.NewPrinter(sf.silent, sf.noColor, false, "")
 	defer printer.Close()
 	printer.Banner()
 	printer.Info("Syncing payload files into %s", payloadDir)
 
-	syncer := payloadsync.New(time.Duration(timeoutSec) * time.Second)
+	syncer := payloadsync.New(time.Duration(sf.timeoutSec) * time.Second)
 	files, err := syncer.Sync(payloadDir)
 	if err != nil {
 		return err
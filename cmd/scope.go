@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/scope"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// scopeFlags holds the include/exclude rules shared by every scan command
+type scopeFlags struct {
+	include     string
+	includeFile string
+	exclude     string
+	excludeFile string
+}
+
+func (s *scopeFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.include, "scope", "", "Comma-separated in-scope rules (host glob, IP/CIDR, or path:<regex>)")
+	fs.StringVar(&s.includeFile, "scope-file", "", "File of in-scope rules, one per line")
+	fs.StringVar(&s.exclude, "out-of-scope", "", "Comma-separated out-of-scope rules; these always win")
+	fs.StringVar(&s.excludeFile, "out-of-scope-file", "", "File of out-of-scope rules, one per line")
+}
+
+func (s *scopeFlags) validate() error {
+	for _, path := range []string{s.includeFile, s.excludeFile} {
+		if path != "" && !utils.PathExists(path) {
+			return fmt.Errorf("scope file not found: %s", path)
+		}
+	}
+	return nil
+}
+
+// build parses the rules. It returns nil when no rules were given.
+func (s *scopeFlags) build() (*scope.Scope, error) {
+	include, err := readRules(s.include, s.includeFile)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := readRules(s.exclude, s.excludeFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	return scope.New(include, exclude)
+}
+
+func readRules(list, path string) ([]string, error) {
+	rules := splitList(list)
+	if path != "" {
+		lines, err := utils.ReadLines(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading scope file: %w", err)
+		}
+		rules = append(rules, lines...)
+	}
+	return rules, nil
+}
+
+// startScope drops out-of-scope targets and logs requests the client skips.
+func startScope(cfg *config.Config, printer *output.Printer) error {
+	if cfg.Scope == nil {
+		return nil
+	}
+	cfg.Scope.OnSkip(func(reason string) {
+		printer.Warning("Skipping out-of-scope request: %s", reason)
+	})
+
+	inScope := func(raw string) bool {
+		parsed, err := url.Parse(raw)
+		return err == nil && cfg.Scope.Check(parsed) == nil
+	}
+
+	urls := cfg.URLs[:0]
+	for _, u := range cfg.URLs {
+		if inScope(u) {
+			urls = append(urls, u)
+		}
+	}
+	cfg.URLs = urls
+
+	targets := cfg.Targets[:0]
+	for _, t := range cfg.Targets {
+		if inScope(t.URL) {
+			targets = append(targets, t)
+		}
+	}
+	cfg.Targets = targets
+
+	if len(cfg.URLs) == 0 {
+		printer.Error("No targets left in scope")
+		return fmt.Errorf("no targets in scope")
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// prepareTargets canonicalizes and dedupes the targets. Targets given
+// without a scheme are probed once per host, https first, then http.
+func prepareTargets(cfg *config.Config, printer *output.Printer) {
+	schemes := probeSchemes(cfg, schemelessHosts(cfg), printer)
+
+	resolve := func(raw string) string {
+		canonical := utils.CanonicalURL(raw)
+		if !strings.HasPrefix(canonical, "//") {
+			return canonical
+		}
+		scheme := "https"
+		if parsed, err := url.Parse(canonical); err == nil && schemes[parsed.Host] != "" {
+			scheme = schemes[parsed.Host]
+		}
+		return scheme + ":" + canonical
+	}
+
+	before := len(cfg.URLs)
+	seen := make(map[string]bool, len(cfg.URLs))
+	urls := cfg.URLs[:0]
+	for _, raw := range cfg.URLs {
+		u := resolve(raw)
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	cfg.URLs = urls
+
+	seen = make(map[string]bool, len(cfg.Targets))
+	targets := cfg.Targets[:0]
+	for _, t := range cfg.Targets {
+		t.URL = resolve(t.URL)
+		key := t.Method + " " + t.URL + "\x00" + string(t.Body)
+		if !seen[key] {
+			seen[key] = true
+			targets = append(targets, t)
+		}
+	}
+	cfg.Targets = targets
+
+	if removed := before - len(cfg.URLs); removed > 0 {
+		printer.Info("Removed %d duplicate target(s)", removed)
+	}
+}
+
+// schemelessHosts lists the hosts of scheme-relative targets
+func schemelessHosts(cfg *config.Config) []string {
+	raw := append([]string{}, cfg.URLs...)
+	for _, t := range cfg.Targets {
+		raw = append(raw, t.URL)
+	}
+
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, r := range raw {
+		canonical := utils.CanonicalURL(r)
+		if !strings.HasPrefix(canonical, "//") {
+			continue
+		}
+		parsed, err := url.Parse(canonical)
+		if err != nil || seen[parsed.Host] {
+			continue
+		}
+		seen[parsed.Host] = true
+		hosts = append(hosts, parsed.Host)
+	}
+	return hosts
+}
+
+// probeSchemes finds the scheme each host answers on, defaulting to https
+func probeSchemes(cfg *config.Config, hosts []string, printer *output.Printer) map[string]string {
+	schemes := make(map[string]string, len(hosts))
+	if len(hosts) == 0 {
+		return schemes
+	}
+	printer.Info("Probing https/http for %d host(s) given without a scheme", len(hosts))
+
+	client := httpclient.New(httpclient.Options{
+		Timeout:   cfg.Timeout,
+		Proxy:     cfg.Proxy,
+		UserAgent: cfg.UserAgent,
+		Headers:   cfg.Headers,
+		Insecure:  true,
+		Scope:     cfg.Scope,
+	})
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, cfg.Concurrency)
+	for _, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(host string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			scheme := "https"
+			if probed, err := client.ProbeScheme("//" + host + "/"); err == nil {
+				scheme = strings.SplitN(probed, ":", 2)[0]
+			} else {
+				printer.Warning("%s answered on neither https nor http; assuming https", host)
+			}
+
+			mu.Lock()
+			schemes[host] = scheme
+			mu.Unlock()
+		}(host)
+	}
+	wg.Wait()
+	return schemes
+}
+
+// perHostConfig returns a copy of cfg that keeps only the first target of
+// each scheme and host, for modules that test the server rather than a path.
+func perHostConfig(cfg *config.Config) *config.Config {
+	scoped := *cfg
+	scoped.URLs = nil
+	scoped.Targets = nil
+
+	seen := make(map[string]bool)
+	for _, t := range cfg.ScanTargets() {
+		parsed, err := url.Parse(t.URL)
+		if err != nil {
+			continue
+		}
+		origin := parsed.Scheme + "://" + parsed.Host
+		if seen[origin] {
+			continue
+		}
+		seen[origin] = true
+		scoped.Targets = append(scoped.Targets, t)
+		scoped.URLs = append(scoped.URLs, t.URL)
+	}
+	return &scoped
+}
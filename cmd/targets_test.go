@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+)
+
+func TestPrepareTargetsProbesSchemeAndDedupes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	cfg := config.Default()
+	cfg.Timeout = 2 * time.Second
+	cfg.URLs = []string{
+		"//" + host + "/admin",
+		"//" + host + "/admin",
+		"https://Example.com:443/a#top",
+		"https://example.com/a",
+	}
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	prepareTargets(cfg, printer)
+
+	want := []string{server.URL + "/admin", "https://example.com/a"}
+	if strings.Join(cfg.URLs, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected targets %v, want %v", cfg.URLs, want)
+	}
+}
+
+func TestPerHostConfigKeepsFirstURLPerOrigin(t *testing.T) {
+	cfg := config.Default()
+	cfg.URLs = []string{
+		"https://a.example.com/admin",
+		"https://a.example.com/login",
+		"http://a.example.com/admin",
+		"https://b.example.com/",
+	}
+
+	hostCfg := perHostConfig(cfg)
+	want := "https://a.example.com/admin http://a.example.com/admin https://b.example.com/"
+	if got := strings.Join(hostCfg.URLs, " "); got != want {
+		t.Fatalf("unexpected per-host targets %q", got)
+	}
+	if len(cfg.URLs) != 4 {
+		t.Fatalf("expected the original config to keep every target")
+	}
+}
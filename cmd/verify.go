@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aether-0/httpsuite/internal/bypass"
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// runVerify replays the bypass findings of an earlier scan against fresh
+// baselines and reports each one as stable, flaky or gone
+func runVerify(args []string) error {
+	cfg, opts, err := parseGlobalFlags(args, "verify")
+	if err != nil {
+		return err
+	}
+
+	results, err := output.ReadResults(opts.input)
+	if err != nil {
+		return fmt.Errorf("error reading findings: %w", err)
+	}
+
+	printer := newPrinter(cfg)
+	defer printer.Close()
+	printer.Banner()
+
+	findings, skipped := replayableFindings(results)
+	if skipped > 0 {
+		printer.Info("Skipping %d result(s) that are not bypass findings", skipped)
+	}
+
+	// -u and -l narrow the findings down to those targets
+	if len(cfg.URLs) > 0 {
+		findings = findingsForTargets(findings, cfg.URLs)
+	} else {
+		for _, finding := range findings {
+			if !containsString(cfg.URLs, finding.Target) {
+				cfg.URLs = append(cfg.URLs, finding.Target)
+			}
+		}
+	}
+	if len(findings) == 0 {
+		printer.Warning("No bypass findings to verify in %s", opts.input)
+		return nil
+	}
+
+	if err := startScope(cfg, printer); err != nil {
+		return err
+	}
+	findings = findingsForTargets(findings, cfg.URLs)
+	if err := startSession(cfg, printer); err != nil {
+		return err
+	}
+
+	printer.Info("Verifying %d finding(s) with %d replay(s) each", len(findings), opts.replays)
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		scanner := bypass.NewScanner(cfg, printer, finding.BaseTarget(), nil, "")
+		v, err := scanner.Verify(finding, opts.replays)
+		if err != nil {
+			printer.Error("Cannot verify %s: %v", finding.URL, err)
+			continue
+		}
+		counts[v.Status]++
+		printer.Result(verifiedResult(finding, v))
+	}
+
+	printer.Info("Verification complete: %d stable, %d flaky, %d gone, %d inconclusive",
+		counts[bypass.VerifyStable], counts[bypass.VerifyFlaky], counts[bypass.VerifyGone], counts[bypass.VerifyInconclusive])
+	return nil
+}
+
+// replayableFindings keeps one copy of each bypass finding that names the
+// technique it came from. Cluster summaries and default requests are not
+// findings.
+func replayableFindings(results []common.ScanResult) ([]common.ScanResult, int) {
+	seen := make(map[string]bool)
+	var findings []common.ScanResult
+	skipped := 0
+	for _, r := range results {
+		if r.Module != "bypass" || r.Technique == "" || r.Target == "" || r.Record != "" {
+			skipped++
+			continue
+		}
+		id := r.ID
+		if id == "" {
+			id = r.FindingID()
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		findings = append(findings, r)
+	}
+	return findings, skipped
+}
+
+func findingsForTargets(findings []common.ScanResult, targets []string) []common.ScanResult {
+	var kept []common.ScanResult
+	for _, finding := range findings {
+		if containsString(targets, finding.Target) || containsString(targets, utils.CanonicalURL(finding.Target)) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// verifiedResult reports the replayed finding under its original ID. Flaky
+// findings drop to tentative confidence and findings that are gone to info.
+// Inconclusive findings, whose replays all failed, keep their severity.
+func verifiedResult(finding common.ScanResult, v bypass.Verification) common.ScanResult {
+	r := finding
+	if r.ID == "" {
+		r.ID = finding.FindingID()
+	}
+	r.Timestamp = time.Time{}
+	r.Count = 0
+	r.Payloads = nil
+	r.Verified = v.Status
+	if v.Status == bypass.VerifyInconclusive {
+		r.Reason = fmt.Sprintf("%s: none of %d replays got a response", v.Status, v.Replays)
+	} else {
+		r.Reason = fmt.Sprintf("%s: %d/%d replays differ from fresh baselines", v.Status, v.Hits, v.Replays-v.Errors)
+		if v.Errors > 0 {
+			r.Reason += fmt.Sprintf(", %d failed", v.Errors)
+		}
+	}
+	r.Detail = finding.Technique + " -> " + r.Reason
+	if v.Last.StatusCode != 0 {
+		r.StatusCode = v.Last.StatusCode
+		r.ContentLength = v.Last.ContentLength
+		r.Title = v.Last.Title
+		r.Fingerprint = v.Last.NormalizedHash
+	}
+
+	switch v.Status {
+	case bypass.VerifyFlaky:
+		r.Confidence = common.ConfidenceTentative
+	case bypass.VerifyGone:
+		r.Severity = common.SeverityInfo
+		r.Confidence = ""
+	}
+	return r
+}
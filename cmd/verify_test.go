@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/aether-0/httpsuite/internal/bypass"
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+func TestReplayableFindingsDedupesAndSkipsNonFindings(t *testing.T) {
+	finding := common.ScanResult{ID: "a1", Module: "bypass", Target: "https://example.com/admin", Technique: "endpath", Payload: "/."}
+	clustered := finding
+	clustered.ID, clustered.ClusterOf, clustered.Record = "c1", finding.ID, common.RecordCluster
+	clustered.Count = 3
+
+	findings, skipped := replayableFindings([]common.ScanResult{
+		{Module: "bypass", Target: "https://example.com/admin", Detail: "default request"},
+		finding,
+		{Module: "cors", Target: "https://example.com", Technique: "origin reflection"},
+		clustered,
+	})
+	if len(findings) != 1 || findings[0].Count != 0 || skipped != 3 {
+		t.Fatalf("unexpected findings %+v, skipped %d", findings, skipped)
+	}
+}
+
+func TestVerifiedResultKeepsIDAndScoresOutcome(t *testing.T) {
+	finding := common.ScanResult{ID: "a1", URL: "https://example.com/admin/.", Module: "bypass", Technique: "endpath",
+		Severity: common.SeverityHigh, Confidence: common.ConfidenceFirm, Count: 2, Payloads: []string{"endpath: /."}}
+
+	stable := verifiedResult(finding, bypass.Verification{Status: bypass.VerifyStable, Hits: 3, Replays: 3})
+	if stable.ID != "a1" || stable.Verified != "stable" || stable.Severity != common.SeverityHigh || stable.Count != 0 {
+		t.Fatalf("unexpected stable result %+v", stable)
+	}
+	if stable.Reason != "stable: 3/3 replays differ from fresh baselines" {
+		t.Fatalf("unexpected reason %q", stable.Reason)
+	}
+
+	if flaky := verifiedResult(finding, bypass.Verification{Status: bypass.VerifyFlaky, Hits: 1, Replays: 3}); flaky.Confidence != common.ConfidenceTentative {
+		t.Fatalf("expected a flaky finding to be tentative, got %+v", flaky)
+	}
+	if gone := verifiedResult(finding, bypass.Verification{Status: bypass.VerifyGone, Replays: 3}); gone.Severity != common.SeverityInfo {
+		t.Fatalf("expected a gone finding to be info, got %+v", gone)
+	}
+
+	partial := verifiedResult(finding, bypass.Verification{Status: bypass.VerifyStable, Hits: 2, Replays: 3, Errors: 1})
+	if partial.Reason != "stable: 2/2 replays differ from fresh baselines, 1 failed" {
+		t.Fatalf("unexpected reason %q", partial.Reason)
+	}
+	failed := verifiedResult(finding, bypass.Verification{Status: bypass.VerifyInconclusive, Replays: 3, Errors: 3})
+	if failed.Severity != common.SeverityHigh || failed.Confidence != common.ConfidenceFirm {
+		t.Fatalf("expected an inconclusive finding to keep its severity, got %+v", failed)
+	}
+}
@@ -0,0 +1,49 @@
+package bypass
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+)
+
+func TestVerbTamperingSendsConfiguredBody(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.Method] = string(data) + "|" + r.Header.Get("Content-Type")
+		mu.Unlock()
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Method = http.MethodPost
+	cfg.Body = []byte(`{"id":1}`)
+	cfg.ContentType = "application/json"
+	cfg.PayloadDir = t.TempDir()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+
+	scanner := NewScanner(cfg, printer, common.Target{URL: server.URL + "/admin"}, []string{"verbs"}, "")
+	scanner.Run()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch} {
+		if bodies[method] != `{"id":1}|application/json` {
+			t.Fatalf("expected %s to carry the body, got %q", method, bodies[method])
+		}
+	}
+	if bodies[http.MethodHead] != "|" {
+		t.Fatalf("expected HEAD without a body, got %q", bodies[http.MethodHead])
+	}
+}
@@ -1,20 +1,17 @@
 package bypass
 
 import (
-	"bufio"
 	"bytes"
-	"crypto/tls"
 	"fmt"
-	"io"
-	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
-	"time"
 	"unicode"
 
 	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
 	"github.com/aether-0/httpsuite/pkg/httpclient"
 	"github.com/aether-0/httpsuite/pkg/output"
 	"github.com/aether-0/httpsuite/pkg/utils"
@@ -22,20 +19,55 @@ import (
 
 const maxRawResponseSample = 64 * 1024
 
+// Techniques lists every technique name accepted by Scanner.Run, in default execution order.
+var Techniques = []string{
+	"headers",
+	"endpaths",
+	"midpaths",
+	"query",
+	"verbs",
+	"verbs-case",
+	"double-encoding",
+	"unicode",
+	"http-versions",
+	"path-case",
+	"target-forms",
+	"chain",
+}
+
+// IsTechnique reports whether name is a known bypass technique.
+func IsTechnique(name string) bool {
+	for _, technique := range Techniques {
+		if technique == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Scanner performs 403 bypass testing
 type Scanner struct {
-	config          *common.Config
+	config          *config.Config
 	printer         *output.Printer
 	client          *httpclient.Client
+	target          common.Target
 	targetURL       string
 	techniques      []string
 	bypassIP        string
 	defaultCL       int
 	defaultBody     httpclient.ResponseSummary
 	calibrationBody httpclient.ResponseSummary
+	noise           []namedBaseline
+	progress        *output.Progress
+	hosts           *Hosts
 
 	verbResultsMu sync.Mutex
 	verbResults   map[string]httpclient.ResponseSummary
+
+	partials    partials
+	chainTop    int
+	chainBudget int
+	targetForm  string
 }
 
 type limitedBodyCapture struct {
@@ -46,12 +78,15 @@ type limitedBodyCapture struct {
 type namedBaseline struct {
 	name    string
 	summary httpclient.ResponseSummary
+	noise   bool
 }
 
 type candidateDecision struct {
 	interesting      bool
 	reason           string
 	suppressedReason string
+	severity         string
+	confidence       string
 }
 
 func (c *limitedBodyCapture) Write(p []byte) (int, error) {
@@ -75,37 +110,59 @@ func (c *limitedBodyCapture) Bytes() []byte {
 	return c.buf.Bytes()
 }
 
-// NewScanner creates a new bypass scanner
-func NewScanner(cfg *common.Config, printer *output.Printer, targetURL string, techniques []string, bypassIP string) *Scanner {
+// NewScanner creates a new bypass scanner for one target. Its method, headers
+// and body form the base request that every technique mutates.
+func NewScanner(cfg *config.Config, printer *output.Printer, target common.Target, techniques []string, bypassIP string) *Scanner {
 	client := httpclient.New(httpclient.Options{
-		Timeout:   cfg.Timeout,
-		Proxy:     cfg.Proxy,
-		UserAgent: cfg.UserAgent,
-		Headers:   cfg.Headers,
-		Retries:   cfg.Retries,
-		Redirect:  cfg.Redirect,
-		Insecure:  true,
+		Timeout:     cfg.Timeout,
+		Proxy:       cfg.Proxy,
+		UserAgent:   cfg.UserAgent,
+		Headers:     cfg.Headers,
+		Retries:     cfg.Retries,
+		Redirect:    cfg.Redirect,
+		Insecure:    true,
+		Session:     cfg.Session,
+		Scope:       cfg.Scope,
+		ContentType: cfg.ContentType,
 	})
 
 	return &Scanner{
 		config:      cfg,
 		printer:     printer,
 		client:      client,
-		targetURL:   targetURL,
+		target:      cfg.ResolveTarget(target),
+		targetURL:   target.URL,
 		techniques:  techniques,
 		bypassIP:    bypassIP,
 		verbResults: make(map[string]httpclient.ResponseSummary),
+		chainTop:    DefaultChainTop,
+		chainBudget: DefaultChainBudget,
+		targetForm:  TargetFormOrigin,
 	}
 }
 
 // Run executes the bypass scan
 func (s *Scanner) Run() {
+	if status, ok := s.config.Preflight.Status(s.targetURL); ok && status != http.StatusUnauthorized && status != http.StatusForbidden {
+		s.printer.Info("Skipping %s: pre-flight returned %d, not 401/403", s.targetURL, status)
+		return
+	}
 	s.printer.Info("Starting 403 bypass scan for: %s", s.targetURL)
 
+	s.progress = s.printer.StartProgress("bypass")
+	defer s.progress.Finish()
+
+	s.progress.AddTotal(2)
 	s.calibrate()
 	s.defaultRequest()
 
-	for _, tech := range s.techniques {
+	techniques := s.orderedTechniques()
+	if !slices.Equal(techniques, s.techniques) {
+		s.printer.Info("Trying the techniques that worked on %s first", originOf(s.targetURL))
+	}
+
+	chain := false
+	for _, tech := range techniques {
 		switch strings.TrimSpace(tech) {
 		case "verbs":
 			s.verbTampering()
@@ -117,25 +174,88 @@ func (s *Scanner) Run() {
 			s.endPathBypass()
 		case "midpaths":
 			s.midPathBypass()
+		case "query":
+			s.queryBypass()
 		case "double-encoding":
 			s.doubleEncoding()
+		case "unicode":
+			s.unicodeEncoding()
 		case "http-versions":
 			s.httpVersions()
 		case "path-case":
 			s.pathCaseSwitching()
+		case "target-forms":
+			s.targetForms()
+		case "chain":
+			// Chaining combines the results of the other techniques, so it runs last
+			chain = true
 		default:
 			s.printer.Warning("Unknown technique: %s", tech)
 		}
 	}
+	if chain {
+		s.chainBypass()
+	}
 }
 
-func (s *Scanner) requestMethod() string {
-	if s.config.Method == "" {
-		return http.MethodGet
+// inspect sends a request through the shared client and records it in the progress display.
+func (s *Scanner) inspect(method, targetURL string, extraHeaders map[string]string) (httpclient.ResponseSummary, error) {
+	summary, err := s.client.InspectPayload(method, targetURL, extraHeaders, nil, injectedValues(targetURL, extraHeaders))
+	s.progress.Request(err)
+	return summary, err
+}
+
+// inspectBase sends the base request (method, headers and body) to a mutated URL.
+func (s *Scanner) inspectBase(targetURL string, extraHeaders map[string]string) (httpclient.ResponseSummary, error) {
+	headers := s.target.RequestHeaders()
+	for k, v := range extraHeaders {
+		headers[k] = v
 	}
-	return s.config.Method
+	summary, err := s.client.InspectPayload(s.requestMethod(), targetURL, headers, s.target.Body, injectedValues(targetURL, extraHeaders))
+	s.progress.Request(err)
+	return summary, err
+}
+
+// inspectVerb sends the base request body with a tampered method.
+func (s *Scanner) inspectVerb(method, targetURL string) (httpclient.ResponseSummary, error) {
+	headers := s.target.Headers
+	var body []byte
+	if utils.MethodAllowsBody(method) {
+		headers = s.target.RequestHeaders()
+		body = s.target.Body
+	}
+	summary, err := s.client.InspectPayload(method, targetURL, headers, body, injectedValues(targetURL, nil))
+	s.progress.Request(err)
+	return summary, err
 }
 
+// injectedValues lists what a request carries that the scanner chose, since
+// block pages often echo it: the request-target as written, with and without
+// its query, and the injected header values. Base request headers such as
+// cookies are left out, so an echoed username still changes the fingerprint.
+func injectedValues(rawURL string, extraHeaders map[string]string) []string {
+	target := rawURL
+	if _, t, err := splitRawURL(rawURL); err == nil {
+		target = t
+	}
+	values := []string{target}
+	if path, _, ok := strings.Cut(target, "?"); ok {
+		values = append(values, path)
+	}
+	for _, value := range extraHeaders {
+		values = append(values, value)
+	}
+	return values
+}
+
+func (s *Scanner) requestMethod() string {
+	return s.target.Method
+}
+
+// calibrate collects responses the target should never reward: random
+// paths, a random method and a random header. Similar responses are clustered
+// and any candidate that falls into a cluster is treated as noise, which
+// catches catch-all pages such as SPA shells served for every path.
 func (s *Scanner) calibrate() {
 	calibrationURL := s.targetURL
 	if !strings.HasSuffix(calibrationURL, "/") {
@@ -143,25 +263,108 @@ func (s *Scanner) calibrate() {
 	}
 	calibrationURL += "calibration_test_" + utils.RandomString(8)
 
-	summary, err := s.client.InspectRequest(http.MethodGet, calibrationURL, nil)
-	if err != nil {
-		s.printer.Warning("Calibration failed: %v", err)
-		return
+	type probe struct {
+		name string
+		send func() (httpclient.ResponseSummary, error)
+	}
+	probes := []probe{
+		{"calibration", func() (httpclient.ResponseSummary, error) {
+			return s.inspect(http.MethodGet, calibrationURL, nil)
+		}},
+		{"random method", func() (httpclient.ResponseSummary, error) {
+			return s.inspectVerb(strings.ToUpper(utils.RandomString(6)), s.targetURL)
+		}},
+		{"random header", func() (httpclient.ResponseSummary, error) {
+			return s.inspectBase(s.targetURL, map[string]string{"X-" + utils.RandomString(8): utils.RandomString(8)})
+		}},
+	}
+	// Random paths do not depend on the target, so they are sent once per
+	// origin and shared with the scanners of its other targets
+	origin := originOf(s.targetURL)
+	originProbes := []probe{
+		{"random path", func() (httpclient.ResponseSummary, error) {
+			return s.inspect(http.MethodGet, origin+"/"+utils.RandomString(10), nil)
+		}},
+		{"random file", func() (httpclient.ResponseSummary, error) {
+			return s.inspect(http.MethodGet, origin+"/"+utils.RandomString(10)+".html", nil)
+		}},
+	}
+	s.progress.AddTotal(len(probes))
+
+	samples := make([]namedBaseline, 0, len(probes)+len(originProbes))
+	for i, probe := range probes {
+		summary, err := probe.send()
+		if err != nil {
+			if i == 0 {
+				s.printer.Warning("Calibration failed: %v", err)
+			}
+			continue
+		}
+		if i == 0 {
+			s.defaultCL = summary.ContentLength
+			s.calibrationBody = summary
+			s.printer.Info("Auto-calibration: status=%d, content-length=%d", summary.StatusCode, summary.ContentLength)
+		}
+		samples = append(samples, namedBaseline{name: probe.name, summary: summary})
 	}
 
-	s.defaultCL = summary.ContentLength
-	s.calibrationBody = summary
-	s.printer.Info("Auto-calibration: status=%d, content-length=%d", summary.StatusCode, summary.ContentLength)
+	shared, cached := s.hosts.originSamples(origin, func() []namedBaseline {
+		s.progress.AddTotal(len(originProbes))
+		var collected []namedBaseline
+		for _, probe := range originProbes {
+			if summary, err := probe.send(); err == nil {
+				collected = append(collected, namedBaseline{name: probe.name, summary: summary})
+			}
+		}
+		return collected
+	})
+	if cached && s.config.Verbose {
+		s.printer.Info("Reusing %d calibration response(s) of %s", len(shared), origin)
+	}
+	samples = append(samples, shared...)
+
+	clusters := s.clusterBaselines(samples)
+	for _, cluster := range clusters {
+		// The calibration response is already a baseline of its own
+		if s.calibrationBody.StatusCode != 0 && cluster.summary == s.calibrationBody {
+			continue
+		}
+		s.noise = append(s.noise, cluster)
+	}
+	if s.config.Verbose {
+		s.printer.Info("Baseline clustering: %d clusters from %d responses", len(clusters), len(samples))
+	}
+}
+
+// clusterBaselines groups similar responses; each cluster is represented by
+// its first member and named after the probes that fell into it.
+func (s *Scanner) clusterBaselines(samples []namedBaseline) []namedBaseline {
+	var clusters []namedBaseline
+	for _, sample := range samples {
+		joined := false
+		for i := range clusters {
+			if s.sameBlockedResponse(sample.summary, clusters[i].summary) {
+				clusters[i].name += ", " + sample.name
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			clusters = append(clusters, namedBaseline{name: sample.name, summary: sample.summary, noise: true})
+		}
+	}
+	return clusters
 }
 
 func (s *Scanner) defaultRequest() {
 	s.printer.SectionHeader("DEFAULT REQUEST")
 
-	summary, err := s.client.InspectRequest(s.requestMethod(), s.targetURL, nil)
+	summary, err := s.inspectBase(s.targetURL, nil)
 	if err != nil {
 		s.printer.Error("Default request failed: %v", err)
 		return
 	}
+	summary = s.learnDynamicMask(summary)
 
 	s.defaultBody = summary
 
@@ -173,24 +376,51 @@ func (s *Scanner) defaultRequest() {
 		Title:         summary.Title,
 		Fingerprint:   summary.NormalizedHash,
 		Module:        "bypass",
+		Target:        s.targetURL,
 		Detail:        "default request",
 	})
 }
 
+// learnDynamicMask repeats the default request and masks the words that
+// changed, so CSRF tokens and request IDs do not make every response unique.
+// It returns first with the mask applied; earlier baselines are masked too.
+func (s *Scanner) learnDynamicMask(first httpclient.ResponseSummary) httpclient.ResponseSummary {
+	second, err := s.inspectBase(s.targetURL, nil)
+	if err != nil {
+		return first
+	}
+	mask := httpclient.LearnDynamicMask(first, second)
+	if mask == nil {
+		return first
+	}
+	s.client.SetDynamicMask(mask)
+	s.calibrationBody = mask.Apply(s.calibrationBody)
+	for i := range s.noise {
+		s.noise[i].summary = mask.Apply(s.noise[i].summary)
+	}
+	if s.config.Verbose {
+		s.printer.Info("Masking dynamic content that changed between two default requests")
+	}
+	return mask.Apply(first)
+}
+
 func (s *Scanner) verbTampering() {
 	s.printer.SectionHeader("VERB TAMPERING")
 
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
-	for _, method := range HTTPMethodsForDir(s.config.PayloadDir) {
+	methods := preferWins(s, "verb tampering", HTTPMethodsForDir(s.config.PayloadDir), trickName)
+	s.progress.AddTotal(len(methods))
+
+	for _, method := range methods {
 		wg.Add(1)
 		sem <- struct{}{}
 		go func(method string) {
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			summary, err := s.client.InspectRequest(method, s.targetURL, nil)
+			summary, err := s.inspectVerb(method, s.targetURL)
 			if err != nil {
 				return
 			}
@@ -203,7 +433,7 @@ func (s *Scanner) verbTampering() {
 
 			s.recordVerbResult(method, summary)
 
-			s.emitBypassResult(s.targetURL, method, "verb tampering", summary, decision.reason)
+			s.emitBypassResult(s.targetURL, method, "verb tampering", method, nil, summary, decision)
 		}(method)
 	}
 
@@ -237,6 +467,8 @@ func (s *Scanner) verbCaseSwitching() {
 		}
 	}
 
+	s.progress.AddTotal(len(workItems))
+
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
@@ -247,7 +479,7 @@ func (s *Scanner) verbCaseSwitching() {
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			summary, err := s.client.InspectRequest(item.method, s.targetURL, nil)
+			summary, err := s.inspectVerb(item.method, s.targetURL)
 			if err != nil {
 				return
 			}
@@ -263,7 +495,7 @@ func (s *Scanner) verbCaseSwitching() {
 				return
 			}
 
-			s.emitBypassResult(s.targetURL, item.method, "verb case switching", summary, decision.reason)
+			s.emitBypassResult(s.targetURL, item.method, "verb case switching", item.method, nil, summary, decision)
 		}(item)
 	}
 
@@ -292,6 +524,9 @@ func (s *Scanner) headerBypass() {
 		parsedURL.Scheme,
 		s.bypassIP,
 	)
+	payloads = preferWins(s, "header bypass", payloads, func(hp HeaderPayload) string { return hp.Key })
+
+	s.progress.AddTotal(len(payloads))
 
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
@@ -307,7 +542,7 @@ func (s *Scanner) headerBypass() {
 				hp.Key: hp.Value,
 			}
 
-			summary, err := s.client.InspectRequest(s.requestMethod(), s.targetURL, extraHeaders)
+			summary, err := s.inspectBase(s.targetURL, extraHeaders)
 			if err != nil {
 				return
 			}
@@ -318,14 +553,13 @@ func (s *Scanner) headerBypass() {
 				return
 			}
 
-			reason := decision.reason
-			if reason != "" {
-				reason = fmt.Sprintf("%s; %s", fmt.Sprintf("%s: %s", hp.Key, hp.Value), reason)
+			if decision.reason != "" {
+				decision.reason = fmt.Sprintf("%s; %s", fmt.Sprintf("%s: %s", hp.Key, hp.Value), decision.reason)
 			} else {
-				reason = fmt.Sprintf("%s: %s", hp.Key, hp.Value)
+				decision.reason = fmt.Sprintf("%s: %s", hp.Key, hp.Value)
 			}
 
-			s.emitBypassResult(s.targetURL, s.requestMethod(), "header bypass", summary, reason)
+			s.emitBypassResult(s.targetURL, s.requestMethod(), "header bypass", hp.Key+": "+hp.Value, extraHeaders, summary, decision)
 		}(hp)
 	}
 
@@ -335,10 +569,13 @@ func (s *Scanner) headerBypass() {
 func (s *Scanner) endPathBypass() {
 	s.printer.SectionHeader("END PATH BYPASS")
 
+	payloads := preferWins(s, "endpath", EndPathPayloadsForDir(s.config.PayloadDir), trickName)
+	s.progress.AddTotal(len(payloads))
+
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
-	for _, payload := range EndPathPayloadsForDir(s.config.PayloadDir) {
+	for _, payload := range payloads {
 		wg.Add(1)
 		sem <- struct{}{}
 		go func(payload string) {
@@ -346,7 +583,7 @@ func (s *Scanner) endPathBypass() {
 			defer func() { <-sem }()
 
 			testURL := utils.JoinURL(s.targetURL, payload)
-			summary, err := s.client.InspectRequest(s.requestMethod(), testURL, nil)
+			summary, err := s.inspectPath(testURL)
 			if err != nil {
 				return
 			}
@@ -357,7 +594,7 @@ func (s *Scanner) endPathBypass() {
 				return
 			}
 
-			s.emitBypassResult(testURL, s.requestMethod(), "endpath", summary, decision.reason)
+			s.emitBypassResult(testURL, s.requestMethod(), "endpath", payload, nil, summary, decision)
 		}(payload)
 	}
 
@@ -395,10 +632,13 @@ func (s *Scanner) midPathBypass() {
 
 	baseURL := parsedURL.Scheme + "://" + parsedURL.Host
 
+	payloads := preferWins(s, "midpath", MidPathPayloadsForDir(s.config.PayloadDir), trickName)
+	s.progress.AddTotal(len(payloads))
+
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
-	for _, payload := range MidPathPayloadsForDir(s.config.PayloadDir) {
+	for _, payload := range payloads {
 		wg.Add(1)
 		sem <- struct{}{}
 		go func(payload string) {
@@ -413,7 +653,7 @@ func (s *Scanner) midPathBypass() {
 				fullpath += "?" + parsedURL.RawQuery
 			}
 
-			summary, err := s.client.InspectRequest(s.requestMethod(), fullpath, nil)
+			summary, err := s.inspectPath(fullpath)
 			if err != nil {
 				return
 			}
@@ -424,7 +664,7 @@ func (s *Scanner) midPathBypass() {
 				return
 			}
 
-			s.emitBypassResult(fullpath, s.requestMethod(), "midpath", summary, decision.reason)
+			s.emitBypassResult(fullpath, s.requestMethod(), "midpath", payload, nil, summary, decision)
 		}(payload)
 	}
 
@@ -463,13 +703,14 @@ func (s *Scanner) doubleEncoding() {
 			encodedURI += "?" + parsedURL.RawQuery
 		}
 
+		s.progress.AddTotal(1)
 		wg.Add(1)
 		sem <- struct{}{}
-		go func(uri string) {
+		go func(uri, payload string) {
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			summary, err := s.client.InspectRequest(s.requestMethod(), uri, nil)
+			summary, err := s.inspectPath(uri)
 			if err != nil {
 				return
 			}
@@ -480,8 +721,8 @@ func (s *Scanner) doubleEncoding() {
 				return
 			}
 
-			s.emitBypassResult(uri, s.requestMethod(), "double encoding", summary, decision.reason)
-		}(encodedURI)
+			s.emitBypassResult(uri, s.requestMethod(), "double encoding", payload, nil, summary, decision)
+		}(encodedURI, modifiedPath)
 	}
 
 	wg.Wait()
@@ -509,6 +750,7 @@ func (s *Scanner) pathCaseSwitching() {
 	}
 
 	variants := utils.GenerateCaseVariants(uriPath, 20)
+	s.progress.AddTotal(len(variants))
 
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
@@ -526,7 +768,7 @@ func (s *Scanner) pathCaseSwitching() {
 			}
 			fullpath += queryStr
 
-			summary, err := s.client.InspectRequest(s.requestMethod(), fullpath, nil)
+			summary, err := s.inspectPath(fullpath)
 			if err != nil {
 				return
 			}
@@ -537,7 +779,7 @@ func (s *Scanner) pathCaseSwitching() {
 				return
 			}
 
-			s.emitBypassResult(fullpath, s.requestMethod(), "path case", summary, decision.reason)
+			s.emitBypassResult(fullpath, s.requestMethod(), "path case", "/"+path, nil, summary, decision)
 		}(path)
 	}
 
@@ -547,19 +789,31 @@ func (s *Scanner) pathCaseSwitching() {
 func (s *Scanner) httpVersions() {
 	s.printer.SectionHeader("HTTP VERSIONS")
 
+	// The HTTP/1.x and 0.9 probes dial the origin directly; forced HTTP/2
+	// goes through the regular client and works behind a proxy
+	versions := HTTPVersions
 	if s.config.Proxy != nil {
-		s.printer.Warning("Skipping HTTP version checks for %s: proxy mode is not supported", s.targetURL)
-		return
+		s.printer.Warning("Skipping raw HTTP version checks for %s: proxy mode is not supported", s.targetURL)
+		versions = nil
 	}
 
-	for _, version := range HTTPVersions {
+	s.progress.AddTotal(len(versions) + 1)
+
+	for _, version := range versions {
 		summary, err := s.requestHTTPVersion(version)
+		s.progress.Request(err)
 		if err != nil {
 			if s.config.Verbose {
 				s.printer.Error("HTTP/%s request failed: %v", version, err)
 			}
 			continue
 		}
+		if !protocolAccepted(version, summary) {
+			if s.config.Verbose {
+				s.printer.Info("Skipping HTTP/%s for %s: not supported by the origin (%d)", version, s.targetURL, summary.StatusCode)
+			}
+			continue
+		}
 
 		decision := s.decideCandidate(summary)
 		if !decision.interesting {
@@ -567,121 +821,117 @@ func (s *Scanner) httpVersions() {
 			continue
 		}
 
-		reason := decision.reason
-		if reason != "" {
-			reason = "HTTP/" + version + "; " + reason
+		if decision.reason != "" {
+			decision.reason = "HTTP/" + version + "; " + decision.reason
 		} else {
-			reason = "HTTP/" + version
+			decision.reason = "HTTP/" + version
 		}
 
-		s.emitBypassResult(s.targetURL, s.requestMethod(), "http version", summary, reason)
+		s.emitBypassResult(s.targetURL, s.requestMethod(), "http version", "HTTP/"+version, nil, summary, decision)
 	}
+
+	s.forcedHTTP2()
 }
 
-func (s *Scanner) requestHTTPVersion(version string) (httpclient.ResponseSummary, error) {
-	parsedURL, err := url.Parse(s.targetURL)
+// forcedHTTP2 sends the base request over HTTP/2 only. Front ends often
+// apply different rules per protocol, or pass HTTP/2 through to a back end
+// that does not enforce the block.
+func (s *Scanner) forcedHTTP2() {
+	summary, err := s.requestHTTP2()
+	s.progress.Request(err)
 	if err != nil {
-		return httpclient.ResponseSummary{}, err
-	}
-
-	addr := parsedURL.Host
-	if parsedURL.Port() == "" {
-		switch parsedURL.Scheme {
-		case "https":
-			addr = net.JoinHostPort(parsedURL.Hostname(), "443")
-		case "http":
-			addr = net.JoinHostPort(parsedURL.Hostname(), "80")
-		default:
-			return httpclient.ResponseSummary{}, fmt.Errorf("unsupported scheme: %s", parsedURL.Scheme)
+		if s.config.Verbose {
+			s.printer.Info("Skipping HTTP/2 for %s: not supported by the origin (%v)", s.targetURL, err)
 		}
+		return
 	}
 
-	dialer := &net.Dialer{Timeout: s.config.Timeout}
-	var conn net.Conn
-	switch parsedURL.Scheme {
-	case "https":
-		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
-			InsecureSkipVerify: true,
-			ServerName:         parsedURL.Hostname(),
-		})
-	case "http":
-		conn, err = dialer.Dial("tcp", addr)
-	default:
-		err = fmt.Errorf("unsupported scheme: %s", parsedURL.Scheme)
-	}
-	if err != nil {
-		return httpclient.ResponseSummary{}, err
+	decision := s.decideCandidate(summary)
+	if !decision.interesting {
+		s.logSuppressed("http version", s.targetURL, s.requestMethod(), decision.suppressedReason)
+		return
 	}
-	defer conn.Close()
 
-	if err := conn.SetDeadline(time.Now().Add(s.config.Timeout)); err != nil {
-		return httpclient.ResponseSummary{}, err
+	if decision.reason != "" {
+		decision.reason = "HTTP/2; " + decision.reason
+	} else {
+		decision.reason = "HTTP/2"
 	}
 
-	targetPath := parsedURL.RequestURI()
-	if targetPath == "" {
-		targetPath = "/"
-	}
+	s.emitBypassResult(s.targetURL, s.requestMethod(), "http version", "HTTP/2", nil, summary, decision)
+}
 
-	method := s.requestMethod()
-
-	var builder strings.Builder
-	builder.WriteString(method)
-	builder.WriteByte(' ')
-	builder.WriteString(targetPath)
-	builder.WriteString(" HTTP/")
-	builder.WriteString(version)
-	builder.WriteString("\r\n")
-	builder.WriteString("Host: ")
-	builder.WriteString(parsedURL.Host)
-	builder.WriteString("\r\n")
-	builder.WriteString("User-Agent: ")
-	builder.WriteString(s.config.UserAgent)
-	builder.WriteString("\r\n")
-	builder.WriteString("Accept: */*\r\n")
-	builder.WriteString("Connection: close\r\n")
-	for key, value := range s.config.Headers {
-		if strings.EqualFold(key, "Host") {
-			continue
-		}
-		builder.WriteString(key)
-		builder.WriteString(": ")
-		builder.WriteString(value)
-		builder.WriteString("\r\n")
-	}
-	builder.WriteString("\r\n")
+func (s *Scanner) requestHTTP2() (httpclient.ResponseSummary, error) {
+	return s.client.HTTP2().InspectPayload(s.requestMethod(), s.targetURL, s.target.RequestHeaders(), s.target.Body, injectedValues(s.targetURL, nil))
+}
 
-	if _, err := io.WriteString(conn, builder.String()); err != nil {
-		return httpclient.ResponseSummary{}, err
+// protocolAccepted reports whether the origin processed a raw request instead
+// of rejecting its protocol version. Servers without HTTP/0.9 support answer
+// the bare request line with an HTTP/1.x 400.
+func protocolAccepted(version string, summary httpclient.ResponseSummary) bool {
+	if summary.StatusCode == http.StatusHTTPVersionNotSupported {
+		return false
 	}
+	return version != "0.9" || summary.StatusCode != http.StatusBadRequest
+}
 
-	reader := bufio.NewReader(conn)
-	dummyReq := &http.Request{
-		Method: method,
-		URL:    parsedURL,
-	}
-	resp, err := http.ReadResponse(reader, dummyReq)
+func (s *Scanner) requestHTTPVersion(version string) (httpclient.ResponseSummary, error) {
+	parsedURL, err := url.Parse(s.targetURL)
 	if err != nil {
 		return httpclient.ResponseSummary{}, err
 	}
-	defer resp.Body.Close()
 
-	capture := &limitedBodyCapture{limit: maxRawResponseSample}
-	buf := make([]byte, 32*1024)
-	contentLength, err := io.CopyBuffer(io.MultiWriter(io.Discard, capture), resp.Body, buf)
-	summary := httpclient.SummarizeResponse(resp, capture.Bytes(), int(contentLength))
-	if err != nil {
-		return summary, err
+	targetPath := parsedURL.RequestURI()
+	if targetPath == "" {
+		targetPath = "/"
+	}
+	if version == "0.9" {
+		return s.requestHTTP09(parsedURL, targetPath)
 	}
 
-	return summary, nil
+	return s.sendRaw(parsedURL, rawRequest{
+		method:   s.requestMethod(),
+		target:   targetPath,
+		version:  version,
+		headers:  s.target.Headers,
+		body:     s.target.Body,
+		injected: injectedValues(targetPath, nil),
+	})
 }
 
 func (s *Scanner) isInteresting(summary httpclient.ResponseSummary) bool {
 	return s.decideCandidate(summary).interesting
 }
 
+// decideCandidate keeps responses that differ from the blocked baselines
+// and scores the ones it keeps.
 func (s *Scanner) decideCandidate(summary httpclient.ResponseSummary) candidateDecision {
+	decision := s.classifyCandidate(summary)
+	if decision.interesting {
+		decision.severity, decision.confidence = s.scoreCandidate(summary)
+	}
+	return decision
+}
+
+// scoreCandidate rates a kept response: a success where the default request
+// was refused is a likely bypass, anything else needs a closer look.
+func (s *Scanner) scoreCandidate(summary httpclient.ResponseSummary) (string, string) {
+	success := summary.StatusCode >= 200 && summary.StatusCode < 300
+	blocked := s.defaultBody.StatusCode == http.StatusUnauthorized || s.defaultBody.StatusCode == http.StatusForbidden
+
+	switch {
+	case success && blocked:
+		return common.SeverityHigh, common.ConfidenceFirm
+	case success:
+		return common.SeverityMedium, common.ConfidenceTentative
+	default:
+		// Redirects, errors and body changes often lead to a login or error
+		// page rather than the resource
+		return common.SeverityLow, common.ConfidenceTentative
+	}
+}
+
+func (s *Scanner) classifyCandidate(summary httpclient.ResponseSummary) candidateDecision {
 	baselines := s.baselineResponses()
 	if len(baselines) == 0 {
 		if standaloneBlockLikeSuccess(summary) {
@@ -696,11 +946,17 @@ func (s *Scanner) decideCandidate(summary httpclient.ResponseSummary) candidateD
 	}
 
 	for _, baseline := range s.namedBaselines() {
-		if s.sameBlockedResponse(summary, baseline.summary) {
+		if !s.sameBlockedResponse(summary, baseline.summary) {
+			continue
+		}
+		if baseline.noise {
 			return candidateDecision{
-				suppressedReason: "matched " + baseline.name + " blocked template",
+				suppressedReason: "fell into the " + baseline.name + " noise cluster",
 			}
 		}
+		return candidateDecision{
+			suppressedReason: "matched " + baseline.name + " blocked template",
+		}
 	}
 
 	if s.probablyFakeBypass(summary, baselines) {
@@ -760,7 +1016,7 @@ func (s *Scanner) baselineResponses() []httpclient.ResponseSummary {
 }
 
 func (s *Scanner) namedBaselines() []namedBaseline {
-	baselines := make([]namedBaseline, 0, 2)
+	baselines := make([]namedBaseline, 0, 2+len(s.noise))
 	if s.defaultBody.StatusCode != 0 {
 		baselines = append(baselines, namedBaseline{
 			name:    "default",
@@ -773,7 +1029,7 @@ func (s *Scanner) namedBaselines() []namedBaseline {
 			summary: s.calibrationBody,
 		})
 	}
-	return baselines
+	return append(baselines, s.noise...)
 }
 
 func (s *Scanner) sameBlockedResponse(left, right httpclient.ResponseSummary) bool {
@@ -873,7 +1129,11 @@ func (s *Scanner) interestingReasons(summary httpclient.ResponseSummary) []strin
 	return reasons
 }
 
-func (s *Scanner) emitBypassResult(targetURL, method, technique string, summary httpclient.ResponseSummary, reason string) {
+func (s *Scanner) emitBypassResult(targetURL, method, technique, payload string, headers map[string]string, summary httpclient.ResponseSummary, decision candidateDecision) {
+	s.recordPartial(technique, method, targetURL, payload, headers, summary, decision)
+	s.learnWin(technique, payload, decision)
+
+	reason := decision.reason
 	detail := technique
 	if reason != "" {
 		detail += " -> " + reason
@@ -889,6 +1149,18 @@ func (s *Scanner) emitBypassResult(targetURL, method, technique string, summary
 		Title:         summary.Title,
 		Fingerprint:   summary.NormalizedHash,
 		Module:        "bypass",
+		Target:        s.targetURL,
+		Technique:     technique,
+		Payload:       payload,
+		Headers:       headers,
+		Base: &common.Request{
+			Method:      s.requestMethod(),
+			Headers:     s.target.Headers,
+			Body:        s.target.Body,
+			ContentType: s.target.ContentType,
+		},
+		Severity:   decision.severity,
+		Confidence: decision.confidence,
 	})
 }
 
@@ -3,10 +3,15 @@ package bypass
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
 	"github.com/aether-0/httpsuite/pkg/httpclient"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/utils"
 )
 
 func TestIsInterestingRejectsSameBlockTemplateWithDifferentStatus(t *testing.T) {
@@ -75,6 +80,92 @@ func TestIsInterestingRejectsFake200BlockPageByMarkerAndLength(t *testing.T) {
 	}
 }
 
+func TestDecideCandidateScoresSuccessOverBlockedBaseline(t *testing.T) {
+	scanner := &Scanner{
+		defaultBody: summarizeHTMLResponse(http.StatusForbidden, htmlPage("403 Forbidden", "Directory access is forbidden.")),
+	}
+
+	success := scanner.decideCandidate(summarizeHTMLResponse(http.StatusOK, htmlPage("Admin", "Welcome to the admin dashboard.")))
+	if !success.interesting || success.severity != common.SeverityHigh || success.confidence != common.ConfidenceFirm {
+		t.Fatalf("expected a high/firm bypass, got %+v", success)
+	}
+
+	redirect := scanner.decideCandidate(summarizeHTMLResponse(http.StatusFound, htmlPage("Moved", "Redirecting to the login page.")))
+	if !redirect.interesting || redirect.severity != common.SeverityLow || redirect.confidence != common.ConfidenceTentative {
+		t.Fatalf("expected a low/tentative candidate, got %+v", redirect)
+	}
+}
+
+func TestCalibrateClustersCatchAllResponsesAsNoise(t *testing.T) {
+	shell := htmlPage("Dashboard", "Loading the application, please wait while scripts start.")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/admin":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(htmlPage("403 Forbidden", "Directory access is forbidden.")))
+		case strings.HasPrefix(r.URL.Path, "/admin/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(htmlPage("404 Not Found", "The requested resource was not found.")))
+		case r.URL.Path == "/console":
+			w.Write([]byte(htmlPage("Admin", "Welcome to the admin console with user management.")))
+		default:
+			// Single-page apps serve the same shell for every unknown route
+			w.Write([]byte(shell))
+		}
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(config.Default(), printer, common.Target{URL: server.URL + "/admin"}, nil, "")
+	scanner.calibrate()
+	scanner.defaultRequest()
+
+	if len(scanner.noise) == 0 {
+		t.Fatal("expected the catch-all page to form a noise cluster")
+	}
+
+	catchAll, err := scanner.inspectBase(server.URL+"/ADMIN", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision := scanner.decideCandidate(catchAll); decision.interesting || !strings.Contains(decision.suppressedReason, "noise cluster") {
+		t.Fatalf("expected the catch-all page to be treated as noise, got %+v", decision)
+	}
+
+	console, err := scanner.inspectBase(server.URL+"/console", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !scanner.decideCandidate(console).interesting {
+		t.Fatal("expected a distinct page to stay interesting")
+	}
+}
+
+func TestDefaultRequestMasksDynamicTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(htmlPage("403 Forbidden", "Access denied. Reference: "+utils.RandomString(12)+" Contact support.")))
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(config.Default(), printer, common.Target{URL: server.URL + "/admin"}, nil, "")
+	scanner.defaultRequest()
+
+	candidate, err := scanner.inspectBase(server.URL+"/admin/.", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if candidate.NormalizedHash != scanner.defaultBody.NormalizedHash {
+		t.Fatalf("expected a stable fingerprint, got %q and %q", scanner.defaultBody.TextSignature, candidate.TextSignature)
+	}
+	if scanner.isInteresting(candidate) {
+		t.Fatal("expected the same block page with a new reference to be ignored")
+	}
+}
+
 func summarizeHTMLResponse(status int, body string) httpclient.ResponseSummary {
 	resp := &http.Response{
 		StatusCode: status,
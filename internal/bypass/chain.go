@@ -0,0 +1,264 @@
+package bypass
+
+import (
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// Default limits for the chain technique
+const (
+	DefaultChainTop    = 3
+	DefaultChainBudget = 50
+)
+
+// Mutation kinds that can be combined; a chain uses at most one of each
+const (
+	mutationVerb   = "verb"
+	mutationHeader = "header"
+	mutationPath   = "path"
+)
+
+// mutation is one trick that produced an interesting response on its own
+type mutation struct {
+	kind    string
+	method  string
+	header  [2]string
+	url     string
+	label   string
+	rank    int
+	order   int
+	summary httpclient.ResponseSummary
+}
+
+// partials collects the candidates kept by the other techniques
+type partials struct {
+	mu        sync.Mutex
+	mutations []mutation
+}
+
+// chainKinds maps a technique to the part of the request it mutates.
+// HTTP version results are not combined since they use a raw connection.
+var chainKinds = map[string]string{
+	"verb tampering":      mutationVerb,
+	"verb case switching": mutationVerb,
+	"header bypass":       mutationHeader,
+	"endpath":             mutationPath,
+	"midpath":             mutationPath,
+	"double encoding":     mutationPath,
+	"path case":           mutationPath,
+	"unicode":             mutationPath,
+	"query":               mutationPath,
+}
+
+// SetChainLimits sets how many partial results per kind the chain technique
+// combines and how many requests it may send.
+func (s *Scanner) SetChainLimits(top, budget int) {
+	s.chainTop = top
+	s.chainBudget = budget
+}
+
+// recordPartial remembers a kept candidate so the chain technique can
+// combine it with others
+func (s *Scanner) recordPartial(technique, method, targetURL, payload string, headers map[string]string, summary httpclient.ResponseSummary, decision candidateDecision) {
+	kind, ok := chainKinds[technique]
+	if !ok {
+		return
+	}
+	m := mutation{
+		kind:    kind,
+		method:  s.requestMethod(),
+		url:     s.targetURL,
+		label:   technique + ": " + payload,
+		rank:    common.SeverityRank(decision.severity),
+		summary: summary,
+	}
+	switch kind {
+	case mutationVerb:
+		m.method = method
+	case mutationHeader:
+		for key, value := range headers {
+			m.header = [2]string{key, value}
+		}
+	case mutationPath:
+		m.url = targetURL
+	}
+
+	s.partials.mu.Lock()
+	m.order = len(s.partials.mutations)
+	s.partials.mutations = append(s.partials.mutations, m)
+	s.partials.mu.Unlock()
+}
+
+// topPartials returns the best n mutations of each kind, most severe first
+func (s *Scanner) topPartials(n int) map[string][]mutation {
+	s.partials.mu.Lock()
+	all := append([]mutation(nil), s.partials.mutations...)
+	s.partials.mu.Unlock()
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].rank != all[j].rank {
+			return all[i].rank > all[j].rank
+		}
+		return all[i].order < all[j].order
+	})
+
+	top := make(map[string][]mutation)
+	for _, m := range all {
+		if len(top[m.kind]) < n {
+			top[m.kind] = append(top[m.kind], m)
+		}
+	}
+	return top
+}
+
+// chainCombinations pairs mutations of different kinds, then adds the
+// verb + header + path triples, stopping at budget.
+func chainCombinations(top map[string][]mutation, budget int) [][]mutation {
+	var combos [][]mutation
+	add := func(chain ...mutation) bool {
+		if len(combos) >= budget {
+			return false
+		}
+		combos = append(combos, chain)
+		return true
+	}
+
+	pairs := [][2]string{
+		{mutationVerb, mutationHeader},
+		{mutationHeader, mutationPath},
+		{mutationVerb, mutationPath},
+	}
+	for _, kinds := range pairs {
+		for _, a := range top[kinds[0]] {
+			for _, b := range top[kinds[1]] {
+				if !add(a, b) {
+					return combos
+				}
+			}
+		}
+	}
+	for _, verb := range top[mutationVerb] {
+		for _, header := range top[mutationHeader] {
+			for _, path := range top[mutationPath] {
+				if !add(verb, header, path) {
+					return combos
+				}
+			}
+		}
+	}
+	return combos
+}
+
+// chainBypass combines the partial results of the other techniques, since
+// WAF-protected targets often need two tricks at once
+func (s *Scanner) chainBypass() {
+	s.printer.SectionHeader("CHAINED BYPASS")
+
+	combos := chainCombinations(s.topPartials(s.chainTop), s.chainBudget)
+	if len(combos) == 0 {
+		s.printer.Info("Not enough partial results from different techniques to chain")
+		return
+	}
+	s.printer.Info("Chaining %d combinations of verb, header and path tricks", len(combos))
+	s.progress.AddTotal(len(combos))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.config.Concurrency)
+
+	for _, chain := range combos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chain []mutation) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			method, targetURL, summary, err := s.inspectChain(chain)
+			if err != nil {
+				return
+			}
+
+			payload := chainLabel(chain)
+			decision := s.decideCandidate(summary)
+			if !decision.interesting {
+				s.logSuppressed("chain", targetURL, method, decision.suppressedReason)
+				return
+			}
+			// A chain that answers like one of its parts adds nothing
+			for _, m := range chain {
+				if m.summary.StatusCode == summary.StatusCode && m.summary.NormalizedHash == summary.NormalizedHash {
+					s.logSuppressed("chain", targetURL, method, "same response as "+m.label)
+					return
+				}
+			}
+
+			s.emitBypassResult(targetURL, method, "chain", payload, chainHeaders(chain), summary, decision)
+		}(chain)
+	}
+
+	wg.Wait()
+}
+
+// inspectChain applies every mutation of chain to the base request
+func (s *Scanner) inspectChain(chain []mutation) (string, string, httpclient.ResponseSummary, error) {
+	method := s.requestMethod()
+	targetURL := s.targetURL
+	headers := s.target.RequestHeaders()
+	body := s.target.Body
+	rawPath := false
+	for _, m := range chain {
+		switch m.kind {
+		case mutationVerb:
+			method = m.method
+			if !utils.MethodAllowsBody(method) {
+				headers = cloneHeaders(s.target.Headers)
+				body = nil
+			}
+		case mutationPath:
+			targetURL = m.url
+			rawPath = s.rawPaths()
+		}
+	}
+	injected := chainHeaders(chain)
+
+	if rawPath {
+		summary, err := s.inspectRaw(method, targetURL, headers, injected, body)
+		return method, targetURL, summary, err
+	}
+	maps.Copy(headers, injected)
+	summary, err := s.client.InspectPayload(method, targetURL, headers, body, injectedValues(targetURL, injected))
+	s.progress.Request(err)
+	return method, targetURL, summary, err
+}
+
+// chainHeaders returns the headers the mutations of chain inject
+func chainHeaders(chain []mutation) map[string]string {
+	headers := make(map[string]string)
+	for _, m := range chain {
+		if m.kind == mutationHeader {
+			headers[m.header[0]] = m.header[1]
+		}
+	}
+	return headers
+}
+
+func chainLabel(chain []mutation) string {
+	labels := make([]string, len(chain))
+	for i, m := range chain {
+		labels[i] = m.label
+	}
+	return strings.Join(labels, " + ")
+}
+
+func cloneHeaders(headers map[string]string) map[string]string {
+	clone := make(map[string]string, len(headers))
+	for k, v := range headers {
+		clone[k] = v
+	}
+	return clone
+}
@@ -0,0 +1,96 @@
+package bypass
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+)
+
+func TestChainCombinationsPairsBeforeTriplesWithinBudget(t *testing.T) {
+	top := map[string][]mutation{
+		mutationVerb:   {{kind: mutationVerb, label: "v1"}},
+		mutationHeader: {{kind: mutationHeader, label: "h1"}, {kind: mutationHeader, label: "h2"}},
+		mutationPath:   {{kind: mutationPath, label: "p1"}},
+	}
+
+	all := chainCombinations(top, 100)
+	// 2 verb+header, 2 header+path, 1 verb+path, then 2 triples
+	if len(all) != 7 {
+		t.Fatalf("expected 7 combinations, got %d", len(all))
+	}
+	if chainLabel(all[0]) != "v1 + h1" || chainLabel(all[6]) != "v1 + h2 + p1" {
+		t.Fatalf("unexpected order: first %q, last %q", chainLabel(all[0]), chainLabel(all[6]))
+	}
+
+	if limited := chainCombinations(top, 3); len(limited) != 3 {
+		t.Fatalf("expected the budget to cap combinations, got %d", len(limited))
+	}
+	if none := chainCombinations(map[string][]mutation{mutationHeader: top[mutationHeader]}, 100); len(none) != 0 {
+		t.Fatalf("expected no chains from a single kind, got %d", len(none))
+	}
+}
+
+func TestChainBypassCombinesHeaderAndPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("X-Original-URL") != ""
+		path := r.URL.Path == "/admin/."
+		switch {
+		case header && path:
+			w.Write([]byte("<html><title>Admin</title><body>Welcome to the admin dashboard</body></html>"))
+		case header:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html><title>Blocked</title><body>Rule 7 matched the rewritten URL</body></html>"))
+		case path:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html><title>Denied</title><body>Normalized path is still protected</body></html>"))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html><title>Forbidden</title><body>You do not have access</body></html>"))
+		}
+	}))
+	defer server.Close()
+
+	out := filepath.Join(t.TempDir(), "scan.jsonl")
+	printer := output.NewPrinter(true, true, true, out)
+
+	cfg := config.Default()
+	cfg.PayloadDir = t.TempDir()
+	target := server.URL + "/admin"
+	scanner := NewScanner(cfg, printer, common.Target{URL: target}, []string{"chain"}, "")
+	scanner.defaultRequest()
+
+	headerOnly, err := scanner.inspectBase(target, map[string]string{"X-Original-URL": "/admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathOnly, err := scanner.inspectBase(target+"/.", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner.emitBypassResult(target, "GET", "header bypass", "X-Original-URL: /admin", map[string]string{"X-Original-URL": "/admin"}, headerOnly, scanner.decideCandidate(headerOnly))
+	scanner.emitBypassResult(target+"/.", "GET", "endpath", "/.", nil, pathOnly, scanner.decideCandidate(pathOnly))
+
+	scanner.chainBypass()
+	printer.Close()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chained string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, `"technique":"chain"`) {
+			chained = line
+		}
+	}
+	if !strings.Contains(chained, `"status_code":200`) || !strings.Contains(chained, "header bypass: X-Original-URL: /admin + endpath: /.") {
+		t.Fatalf("expected a chained 200 result, got:\n%s", data)
+	}
+}
@@ -0,0 +1,196 @@
+package bypass
+
+import (
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// techniqueNames maps the technique label of a result to its --techniques name
+var techniqueNames = map[string]string{
+	"verb tampering":      "verbs",
+	"verb case switching": "verbs-case",
+	"header bypass":       "headers",
+	"endpath":             "endpaths",
+	"midpath":             "midpaths",
+	"query":               "query",
+	"double encoding":     "double-encoding",
+	"unicode":             "unicode",
+	"http version":        "http-versions",
+	"path case":           "path-case",
+	"target form":         "target-forms",
+	"chain":               "chain",
+}
+
+// Hosts shares what scanners learn about an origin with the scanners of
+// other targets on it: the origin-wide calibration responses, and the tricks
+// that produced findings so they are tried first. A nil *Hosts shares
+// nothing.
+type Hosts struct {
+	mu      sync.Mutex
+	origins map[string]*hostState
+}
+
+type hostState struct {
+	// samplesMu is held while the first scanner collects samples
+	samplesMu sync.Mutex
+	samples   []namedBaseline
+	// wins scores tricks per technique label, weighted by severity
+	wins map[string]map[string]int
+}
+
+// NewHosts returns an empty store for one scan
+func NewHosts() *Hosts {
+	return &Hosts{origins: make(map[string]*hostState)}
+}
+
+// SetHosts shares calibration and successful tricks with other scanners
+func (s *Scanner) SetHosts(hosts *Hosts) {
+	s.hosts = hosts
+}
+
+// stateLocked returns the state of origin, creating it. h.mu must be held.
+func (h *Hosts) stateLocked(origin string) *hostState {
+	state, ok := h.origins[origin]
+	if !ok {
+		state = &hostState{wins: make(map[string]map[string]int)}
+		h.origins[origin] = state
+	}
+	return state
+}
+
+// originSamples returns the origin-wide calibration responses, collecting
+// them on first use, and whether they were shared by an earlier scanner.
+// Scanners of the same origin wait for the first one.
+func (h *Hosts) originSamples(origin string, collect func() []namedBaseline) ([]namedBaseline, bool) {
+	if h == nil {
+		return collect(), false
+	}
+	h.mu.Lock()
+	state := h.stateLocked(origin)
+	h.mu.Unlock()
+
+	state.samplesMu.Lock()
+	defer state.samplesMu.Unlock()
+	if len(state.samples) > 0 {
+		return state.samples, true
+	}
+	state.samples = collect()
+	return state.samples, false
+}
+
+func (h *Hosts) recordWin(origin, technique, trick string, weight int) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state := h.stateLocked(origin)
+	if state.wins[technique] == nil {
+		state.wins[technique] = make(map[string]int)
+	}
+	state.wins[technique][trick] += weight
+}
+
+// wins returns a copy of the trick scores of technique on origin
+func (h *Hosts) wins(origin, technique string) map[string]int {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.origins[origin]
+	if !ok {
+		return nil
+	}
+	scores := make(map[string]int, len(state.wins[technique]))
+	for trick, score := range state.wins[technique] {
+		scores[trick] = score
+	}
+	return scores
+}
+
+// techniqueScores sums the trick scores of origin per --techniques name
+func (h *Hosts) techniqueScores(origin string) map[string]int {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.origins[origin]
+	if !ok {
+		return nil
+	}
+	scores := make(map[string]int)
+	for technique, tricks := range state.wins {
+		for _, score := range tricks {
+			scores[techniqueNames[technique]] += score
+		}
+	}
+	return scores
+}
+
+// originOf returns scheme://host of rawURL, or rawURL when it does not parse
+func originOf(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return rawURL
+	}
+	return parsedURL.Scheme + "://" + parsedURL.Host
+}
+
+// trickOf names what a result reused on another path: the header name for
+// header tricks, whose values usually contain the path, else the payload
+func trickOf(technique, payload string) string {
+	if technique == "header bypass" {
+		key, _, _ := strings.Cut(payload, ":")
+		return strings.TrimSpace(key)
+	}
+	return payload
+}
+
+// learnWin remembers that a trick produced a finding on this origin
+func (s *Scanner) learnWin(technique, payload string, decision candidateDecision) {
+	weight := 1 + max(common.SeverityRank(decision.severity), 0)
+	s.hosts.recordWin(originOf(s.targetURL), technique, trickOf(technique, payload), weight)
+}
+
+// orderedTechniques moves the techniques that worked on other paths of the
+// origin to the front, keeping the configured order otherwise
+func (s *Scanner) orderedTechniques() []string {
+	scores := s.hosts.techniqueScores(originOf(s.targetURL))
+	techniques := slices.Clone(s.techniques)
+	if len(scores) == 0 {
+		return techniques
+	}
+	sort.SliceStable(techniques, func(i, j int) bool {
+		return scores[strings.TrimSpace(techniques[i])] > scores[strings.TrimSpace(techniques[j])]
+	})
+	return techniques
+}
+
+// trickName is the trick of a payload that is reused as is
+func trickName(payload string) string {
+	return payload
+}
+
+// preferWins moves the tricks that worked on other paths of the origin to
+// the front of items, best first
+func preferWins[T any](s *Scanner, technique string, items []T, trick func(T) string) []T {
+	scores := s.hosts.wins(originOf(s.targetURL), technique)
+	if len(scores) == 0 {
+		return items
+	}
+	sorted := slices.Clone(items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return scores[trick(sorted[i])] > scores[trick(sorted[j])]
+	})
+	return sorted
+}
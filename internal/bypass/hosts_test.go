@@ -0,0 +1,81 @@
+package bypass
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+)
+
+func TestHostsShareCalibrationAndTryWinningTricksFirst(t *testing.T) {
+	var rootProbes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("X-Original-URL") != "":
+			w.Write([]byte(htmlPage("Admin", "Welcome to the admin console with user management.")))
+		case r.URL.Path == "/admin" || r.URL.Path == "/secret":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(htmlPage("403 Forbidden", "Directory access is forbidden.")))
+		default:
+			if strings.Count(r.URL.Path, "/") == 1 {
+				rootProbes.Add(1)
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(htmlPage("404 Not Found", "The requested resource was not found.")))
+		}
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	hosts := NewHosts()
+
+	first := NewScanner(config.Default(), printer, common.Target{URL: server.URL + "/admin"}, []string{"endpaths", "headers"}, "")
+	first.SetHosts(hosts)
+	first.calibrate()
+	first.defaultRequest()
+	first.headerBypass()
+
+	second := NewScanner(config.Default(), printer, common.Target{URL: server.URL + "/secret"}, []string{"endpaths", "headers"}, "")
+	second.SetHosts(hosts)
+	second.calibrate()
+
+	if got := rootProbes.Load(); got != 2 {
+		t.Fatalf("expected the origin probes to be sent once, got %d root requests", got)
+	}
+	if len(second.noise) == 0 {
+		t.Fatal("expected the shared origin responses to become baselines")
+	}
+	if got := second.orderedTechniques(); got[0] != "headers" {
+		t.Fatalf("expected the header technique first, got %v", got)
+	}
+
+	payloads := preferWins(second, "header bypass", BuildHeaderPayloadsForDir("", second.targetURL, "/secret", "", "http", ""),
+		func(hp HeaderPayload) string { return hp.Key })
+	if payloads[0].Key != "X-Original-URL" {
+		t.Fatalf("expected X-Original-URL first, got %s", payloads[0].Key)
+	}
+}
+
+func TestNilHostsSharesNothing(t *testing.T) {
+	var hosts *Hosts
+	hosts.recordWin("http://example.com", "endpath", "/.", 1)
+	if wins := hosts.wins("http://example.com", "endpath"); wins != nil {
+		t.Fatalf("expected no wins, got %v", wins)
+	}
+	calls := 0
+	collect := func() []namedBaseline {
+		calls++
+		return []namedBaseline{{name: "random path"}}
+	}
+	hosts.originSamples("http://example.com", collect)
+	hosts.originSamples("http://example.com", collect)
+	if calls != 2 {
+		t.Fatalf("expected every scanner to collect its own samples, got %d calls", calls)
+	}
+}
@@ -168,7 +168,9 @@ var HTTPMethods = []string{
 }
 
 // HTTPVersions contains raw protocol versions to test directly against the origin.
+// HTTP/2 is negotiated separately since it is not a text protocol.
 var HTTPVersions = []string{
+	"0.9",
 	"1.0",
 	"1.1",
 }
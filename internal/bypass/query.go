@@ -0,0 +1,115 @@
+package bypass
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// queryTargets builds request targets that alter the query string and path
+// parameters of path. Tomcat and Spring strip ";params" and parse "?" and
+// "#" differently from the proxies that usually enforce the ACL.
+func queryTargets(path, rawQuery string) []string {
+	query := ""
+	if rawQuery != "" {
+		query = "?" + rawQuery
+	}
+	appendQuery := func(prefix string) string {
+		if rawQuery == "" {
+			return prefix
+		}
+		return prefix + "&" + rawQuery
+	}
+
+	seen := map[string]struct{}{path + query: {}}
+	var targets []string
+	add := func(target string) {
+		if _, ok := seen[target]; ok {
+			return
+		}
+		seen[target] = struct{}{}
+		targets = append(targets, target)
+	}
+
+	add(path + "?" + rawQuery)
+	add(path + "??" + rawQuery)
+	add(path + appendQuery("?a=b"))
+	add(path + ";jsessionid=1" + query)
+	add(path + "#" + query)
+	add(path + query + "#")
+	add(path + "%3f" + rawQuery)
+	add(path + "%3F" + rawQuery)
+	add(path + "%253f" + rawQuery)
+
+	if rawQuery != "" {
+		// Parameter pollution: the ACL and the application may read
+		// different copies of a duplicated parameter
+		add(path + "?" + rawQuery + "&" + rawQuery)
+		for _, pair := range strings.Split(rawQuery, "&") {
+			name, _, _ := strings.Cut(pair, "=")
+			if name != "" {
+				add(path + "?" + name + "=&" + rawQuery)
+			}
+		}
+	}
+
+	// Matrix parameters after each segment
+	for i := 1; i <= len(path); i++ {
+		if i < len(path) && path[i] != '/' {
+			continue
+		}
+		if path[i-1] == '/' {
+			continue
+		}
+		add(path[:i] + ";a=b" + path[i:] + query)
+		add(path[:i] + ";" + path[i:] + query)
+	}
+	return targets
+}
+
+func (s *Scanner) queryBypass() {
+	s.printer.SectionHeader("QUERY AND PATH PARAMETERS")
+
+	parsedURL, err := url.Parse(s.targetURL)
+	if err != nil {
+		s.printer.Error("Error parsing URL: %v", err)
+		return
+	}
+
+	pathValue := parsedURL.EscapedPath()
+	if pathValue == "" {
+		pathValue = "/"
+	}
+	baseURL := parsedURL.Scheme + "://" + parsedURL.Host
+
+	targets := queryTargets(pathValue, parsedURL.RawQuery)
+	s.progress.AddTotal(len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.config.Concurrency)
+
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fullpath := baseURL + target
+			summary, err := s.inspectPath(fullpath)
+			if err != nil {
+				return
+			}
+
+			decision := s.decideCandidate(summary)
+			if !decision.interesting {
+				s.logSuppressed("query", fullpath, s.requestMethod(), decision.suppressedReason)
+				return
+			}
+
+			s.emitBypassResult(fullpath, s.requestMethod(), "query", target, nil, summary, decision)
+		}(target)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,36 @@
+package bypass
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQueryTargetsKeepOriginalQuery(t *testing.T) {
+	targets := queryTargets("/api/admin", "id=1")
+	for _, want := range []string{
+		"/api/admin??id=1",
+		"/api/admin?a=b&id=1",
+		"/api/admin;jsessionid=1?id=1",
+		"/api/admin%3fid=1",
+		"/api/admin?id=1&id=1",
+		"/api/admin?id=&id=1",
+		"/api;a=b/admin?id=1",
+		"/api/admin;?id=1",
+	} {
+		if !slices.Contains(targets, want) {
+			t.Fatalf("expected %q in %v", want, targets)
+		}
+	}
+	if slices.Contains(targets, "/api/admin?id=1") {
+		t.Fatal("expected the original target to be skipped")
+	}
+}
+
+func TestQueryTargetsWithoutQuery(t *testing.T) {
+	targets := queryTargets("/admin/", "")
+	for _, want := range []string{"/admin/?", "/admin/?a=b", "/admin/#", "/admin;a=b/"} {
+		if !slices.Contains(targets, want) {
+			t.Fatalf("expected %q in %v", want, targets)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package bypass
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+)
+
+// Request-target forms for raw path requests (RFC 9112, section 3.2)
+const (
+	TargetFormOrigin   = "origin"
+	TargetFormAbsolute = "absolute"
+)
+
+// TargetForms lists the accepted --target-form values
+var TargetForms = []string{TargetFormOrigin, TargetFormAbsolute}
+
+// rawRequest is written to the connection as-is; target is the
+// request-target exactly as it should appear on the wire.
+type rawRequest struct {
+	method  string
+	target  string
+	version string
+	host    string
+	headers map[string]string
+	body    []byte
+
+	// injected are the payload values of the request, see injectedValues
+	injected []string
+}
+
+// SetTargetForm selects how raw path requests write their request-target
+func (s *Scanner) SetTargetForm(form string) {
+	s.targetForm = form
+}
+
+// rawPaths reports whether path payloads can bypass Go's URL handling. Raw
+// requests are dialed directly, so a proxy forces the normal client.
+func (s *Scanner) rawPaths() bool {
+	return s.config.Proxy == nil
+}
+
+// splitRawURL separates scheme://host from the rest of a URL built by string
+// concatenation, without parsing or cleaning the path.
+func splitRawURL(rawURL string) (origin, target string, err error) {
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok || scheme == "" {
+		return "", "", fmt.Errorf("invalid URL %q", rawURL)
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		return rawURL, "/", nil
+	}
+	if end == 0 {
+		return "", "", fmt.Errorf("invalid URL %q", rawURL)
+	}
+	target = rest[end:]
+	if target[0] != '/' {
+		target = "/" + target
+	}
+	return scheme + "://" + rest[:end], target, nil
+}
+
+// inspectRaw sends a request whose path is written byte-for-byte, in the
+// configured request-target form. extraHeaders are injected over headers.
+func (s *Scanner) inspectRaw(method, rawURL string, headers, extraHeaders map[string]string, body []byte) (httpclient.ResponseSummary, error) {
+	summary, err := s.requestRaw(method, rawURL, headers, extraHeaders, body)
+	s.progress.Request(err)
+	return summary, err
+}
+
+func (s *Scanner) requestRaw(method, rawURL string, headers, extraHeaders map[string]string, body []byte) (httpclient.ResponseSummary, error) {
+	origin, target, err := splitRawURL(rawURL)
+	if err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	parsedOrigin, err := url.Parse(origin)
+	if err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+
+	// Scope path rules must see the payload. Payloads such as %u0061 are not
+	// valid URL escapes, so those are checked byte-for-byte.
+	dialURL, err := url.Parse(rawURL)
+	if err != nil {
+		rawPath, _, _ := strings.Cut(target, "?")
+		dialURL = &url.URL{Scheme: parsedOrigin.Scheme, Host: parsedOrigin.Host, Path: rawPath}
+	}
+
+	if s.targetForm == TargetFormAbsolute {
+		target = origin + target
+	}
+	merged := maps.Clone(headers)
+	if merged == nil {
+		merged = make(map[string]string, len(extraHeaders))
+	}
+	maps.Copy(merged, extraHeaders)
+	return s.sendRaw(dialURL, rawRequest{
+		method:   method,
+		target:   target,
+		version:  "1.1",
+		headers:  merged,
+		body:     body,
+		injected: injectedValues(target, extraHeaders),
+	})
+}
+
+// inspectPath sends the base request to a path payload, raw when possible
+func (s *Scanner) inspectPath(rawURL string) (httpclient.ResponseSummary, error) {
+	if !s.rawPaths() {
+		return s.inspectBase(rawURL, nil)
+	}
+	return s.inspectRaw(s.requestMethod(), rawURL, s.target.RequestHeaders(), nil, s.target.Body)
+}
+
+// sendRaw writes req over a fresh connection to target's host and reads one
+// response. Configured, session and request headers are added, and the
+// request is checked against the scope like one sent through the client.
+func (s *Scanner) sendRaw(target *url.URL, req rawRequest) (httpclient.ResponseSummary, error) {
+	headers := s.config.Session.MergeHeaders(s.targetURL, s.config.Headers)
+	// Request headers replace configured ones of any case, as in the client
+	for k, v := range req.headers {
+		for existing := range headers {
+			if strings.EqualFold(existing, k) {
+				delete(headers, existing)
+			}
+		}
+		headers[k] = v
+	}
+	checked := headers
+	if req.host != "" {
+		checked = maps.Clone(headers)
+		checked["Host"] = req.host
+	}
+	if err := s.client.CheckRequest(target, checked); err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+
+	conn, err := s.client.Dial(target, nil)
+	if err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.config.Timeout)); err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+
+	host := req.host
+	if host == "" {
+		host = target.Host
+	}
+
+	var builder strings.Builder
+	builder.WriteString(req.method)
+	builder.WriteByte(' ')
+	builder.WriteString(req.target)
+	builder.WriteString(" HTTP/")
+	builder.WriteString(req.version)
+	builder.WriteString("\r\n")
+	builder.WriteString("Host: ")
+	builder.WriteString(host)
+	builder.WriteString("\r\n")
+	builder.WriteString("User-Agent: ")
+	builder.WriteString(s.config.UserAgent)
+	builder.WriteString("\r\n")
+	builder.WriteString("Accept: */*\r\n")
+	builder.WriteString("Connection: close\r\n")
+	hasContentType := false
+	for key, value := range headers {
+		if strings.EqualFold(key, "Host") || strings.EqualFold(key, "Content-Length") {
+			continue
+		}
+		hasContentType = hasContentType || strings.EqualFold(key, "Content-Type")
+		builder.WriteString(key)
+		builder.WriteString(": ")
+		builder.WriteString(value)
+		builder.WriteString("\r\n")
+	}
+	if len(req.body) > 0 {
+		if s.target.ContentType != "" && !hasContentType {
+			builder.WriteString("Content-Type: ")
+			builder.WriteString(s.target.ContentType)
+			builder.WriteString("\r\n")
+		}
+		builder.WriteString("Content-Length: ")
+		builder.WriteString(strconv.Itoa(len(req.body)))
+		builder.WriteString("\r\n")
+	}
+	builder.WriteString("\r\n")
+	builder.Write(req.body)
+
+	if _, err := io.WriteString(conn, builder.String()); err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+
+	return s.readRawResponse(bufio.NewReader(conn), req.method, req.injected)
+}
+
+// readRawResponse reads one HTTP/1.x response from reader and summarizes it
+func (s *Scanner) readRawResponse(reader *bufio.Reader, method string, injected []string) (httpclient.ResponseSummary, error) {
+	resp, err := http.ReadResponse(reader, &http.Request{Method: method})
+	if err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	defer resp.Body.Close()
+
+	capture := &limitedBodyCapture{limit: maxRawResponseSample}
+	buf := make([]byte, 32*1024)
+	contentLength, err := io.CopyBuffer(capture, resp.Body, buf)
+	return s.client.Summarize(resp, capture.Bytes(), int(contentLength), injected...), err
+}
+
+// requestHTTP09 sends a real HTTP/0.9 request: a bare "GET /path" line
+// without version or headers. A 0.9 reply is the body alone, so it gets the
+// default request's status and is compared to the baselines by its body.
+// Servers without 0.9 support answer with an HTTP/1.x status line instead.
+func (s *Scanner) requestHTTP09(target *url.URL, requestTarget string) (httpclient.ResponseSummary, error) {
+	if err := s.client.CheckRequest(target, nil); err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	conn, err := s.client.Dial(target, nil)
+	if err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.config.Timeout)); err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	if _, err := io.WriteString(conn, "GET "+requestTarget+"\r\n"); err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	// Servers that wait for headers see the end of the request
+	if closer, ok := conn.(interface{ CloseWrite() error }); ok {
+		if err := closer.CloseWrite(); err != nil {
+			return httpclient.ResponseSummary{}, err
+		}
+	}
+
+	injected := injectedValues(requestTarget, nil)
+	reader := bufio.NewReader(conn)
+	if prefix, err := reader.Peek(len("HTTP/")); err == nil && string(prefix) == "HTTP/" {
+		return s.readRawResponse(reader, http.MethodGet, injected)
+	}
+
+	// The body ends when the server closes the connection
+	capture := &limitedBodyCapture{limit: maxRawResponseSample}
+	contentLength, err := io.Copy(capture, reader)
+	if contentLength == 0 {
+		if err == nil {
+			err = fmt.Errorf("empty HTTP/0.9 response")
+		}
+		return httpclient.ResponseSummary{}, err
+	}
+	resp := &http.Response{StatusCode: s.defaultBody.StatusCode, Header: http.Header{}}
+	return s.client.Summarize(resp, capture.Bytes(), int(contentLength), injected...), nil
+}
+
+// targetFormVariant is one request-target sent by the target-forms technique
+type targetFormVariant struct {
+	label  string
+	target string
+	host   string
+}
+
+func targetFormVariants(origin, target string) []targetFormVariant {
+	return []targetFormVariant{
+		{"absolute-form", origin + target, ""},
+		{"absolute-form, Host: localhost", origin + target, "localhost"},
+		{"asterisk-form", "*", ""},
+	}
+}
+
+// targetForms sends the base request with absolute-form and asterisk-form
+// request-targets, which front ends and back ends often route differently
+func (s *Scanner) targetForms() {
+	s.printer.SectionHeader("REQUEST TARGET FORMS")
+
+	if !s.rawPaths() {
+		s.printer.Info("Skipping request-target forms: raw requests cannot go through a proxy")
+		return
+	}
+	origin, target, err := splitRawURL(s.targetURL)
+	if err != nil {
+		s.printer.Error("Error parsing URL: %v", err)
+		return
+	}
+
+	variants := targetFormVariants(origin, target)
+	s.progress.AddTotal(len(variants))
+
+	for _, v := range variants {
+		if v.host != "" && s.config.Scope.CheckHost(v.host) != nil {
+			s.progress.Request(nil)
+			continue
+		}
+		summary, err := s.requestTargetForm(v)
+		s.progress.Request(err)
+		if err != nil {
+			if s.config.Verbose {
+				s.printer.Error("%s request failed: %v", v.label, err)
+			}
+			continue
+		}
+
+		decision := s.decideCandidate(summary)
+		if !decision.interesting {
+			s.logSuppressed("target form", s.targetURL, s.requestMethod(), decision.suppressedReason)
+			continue
+		}
+		s.emitBypassResult(s.targetURL, s.requestMethod(), "target form", v.label, nil, summary, decision)
+	}
+}
+
+func (s *Scanner) requestTargetForm(v targetFormVariant) (httpclient.ResponseSummary, error) {
+	parsedURL, err := url.Parse(s.targetURL)
+	if err != nil {
+		return httpclient.ResponseSummary{}, err
+	}
+	return s.sendRaw(parsedURL, rawRequest{
+		method:   s.requestMethod(),
+		target:   v.target,
+		version:  "1.1",
+		host:     v.host,
+		headers:  s.target.RequestHeaders(),
+		body:     s.target.Body,
+		injected: injectedValues(v.target, nil),
+	})
+}
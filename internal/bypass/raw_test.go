@@ -0,0 +1,373 @@
+package bypass
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+	"github.com/aether-0/httpsuite/pkg/output"
+	"github.com/aether-0/httpsuite/pkg/scope"
+)
+
+func TestSplitRawURLKeepsPathBytes(t *testing.T) {
+	for _, tc := range []struct {
+		in, origin, target string
+	}{
+		{"https://example.com/..;/admin", "https://example.com", "/..;/admin"},
+		{"https://example.com//admin/./%2e/", "https://example.com", "//admin/./%2e/"},
+		{"http://example.com:8080", "http://example.com:8080", "/"},
+		{"http://example.com?a=1", "http://example.com", "/?a=1"},
+	} {
+		origin, target, err := splitRawURL(tc.in)
+		if err != nil || origin != tc.origin || target != tc.target {
+			t.Fatalf("splitRawURL(%q) = %q, %q, %v", tc.in, origin, target, err)
+		}
+	}
+	if _, _, err := splitRawURL("example.com/admin"); err == nil {
+		t.Fatal("expected an error without a scheme")
+	}
+}
+
+func TestPathPayloadsAreSentRaw(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Record the request line and Host header exactly as they arrive
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			requestLine, _ := reader.ReadString('\n')
+			host := ""
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == "\r\n" {
+					break
+				}
+				if value, ok := strings.CutPrefix(line, "Host: "); ok {
+					host = strings.TrimSpace(value)
+				}
+			}
+			lines <- strings.TrimSpace(requestLine) + " " + host
+			conn.Write([]byte("HTTP/1.1 403 Forbidden\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"))
+			conn.Close()
+		}
+	}()
+
+	host := listener.Addr().String()
+	base := "http://" + host
+	cfg := config.Default()
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(cfg, printer, common.Target{URL: base + "/admin"}, nil, "")
+
+	if _, err := scanner.inspectPath(base + "/..;/admin/%2e/"); err != nil {
+		t.Fatal(err)
+	}
+	scanner.SetTargetForm(TargetFormAbsolute)
+	if _, err := scanner.inspectPath(base + "//admin"); err != nil {
+		t.Fatal(err)
+	}
+	scanner.SetTargetForm(TargetFormOrigin)
+	scanner.targetForms()
+
+	want := []string{
+		"GET /..;/admin/%2e/ HTTP/1.1 " + host,
+		"GET " + base + "//admin HTTP/1.1 " + host,
+		"GET " + base + "/admin HTTP/1.1 " + host,
+		"GET " + base + "/admin HTTP/1.1 localhost",
+		"GET * HTTP/1.1 " + host,
+	}
+	for _, expected := range want {
+		if got := <-lines; got != expected {
+			t.Fatalf("expected request %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestHTTPVersionsForcesHTTP2AndSkipsUnsupported(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 {
+			w.Write([]byte("<html><title>Admin</title><body>Welcome to the admin dashboard</body></html>"))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<html><title>Forbidden</title><body>You do not have access</body></html>"))
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	out := filepath.Join(t.TempDir(), "scan.jsonl")
+	printer := output.NewPrinter(true, true, true, out)
+
+	cfg := config.Default()
+	scanner := NewScanner(cfg, printer, common.Target{URL: server.URL + "/admin"}, []string{"http-versions"}, "")
+	scanner.defaultRequest()
+	scanner.httpVersions()
+	printer.Close()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"payload":"HTTP/2"`) || !strings.Contains(string(data), `"status_code":200`) {
+		t.Fatalf("expected a forced HTTP/2 result, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "HTTP/0.9") {
+		t.Fatalf("expected the rejected HTTP/0.9 request to be skipped, got:\n%s", data)
+	}
+}
+
+func TestHTTPVersionsForcesHTTP2BehindProxy(t *testing.T) {
+	origin := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 {
+			w.Write([]byte("<html><title>Admin</title><body>Welcome to the admin dashboard</body></html>"))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<html><title>Forbidden</title><body>You do not have access</body></html>"))
+	}))
+	origin.EnableHTTP2 = true
+	origin.StartTLS()
+	defer origin.Close()
+
+	var tunnels atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		tunnels.Add(1)
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		go func() {
+			io.Copy(upstream, conn)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	defer proxy.Close()
+
+	out := filepath.Join(t.TempDir(), "scan.jsonl")
+	printer := output.NewPrinter(true, true, true, out)
+
+	cfg := config.Default()
+	cfg.Proxy, _ = url.Parse(proxy.URL)
+	scanner := NewScanner(cfg, printer, common.Target{URL: origin.URL + "/admin"}, []string{"http-versions"}, "")
+	scanner.defaultRequest()
+	scanner.httpVersions()
+	printer.Close()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"payload":"HTTP/2"`) {
+		t.Fatalf("expected a forced HTTP/2 result behind the proxy, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "HTTP/1.0") || strings.Contains(string(data), "HTTP/0.9") {
+		t.Fatalf("expected the raw version probes to be skipped, got:\n%s", data)
+	}
+	if tunnels.Load() < 2 {
+		t.Fatalf("expected the default and HTTP/2 requests to go through the proxy, got %d tunnels", tunnels.Load())
+	}
+}
+
+func TestProtocolAccepted(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		status  int
+		want    bool
+	}{
+		{"0.9", http.StatusBadRequest, false},
+		{"0.9", http.StatusOK, true},
+		{"1.0", http.StatusBadRequest, true},
+		{"1.1", http.StatusHTTPVersionNotSupported, false},
+	} {
+		if got := protocolAccepted(tc.version, httpclient.ResponseSummary{StatusCode: tc.status}); got != tc.want {
+			t.Fatalf("protocolAccepted(%s, %d) = %v, want %v", tc.version, tc.status, got, tc.want)
+		}
+	}
+}
+
+func TestRawRequestsAreCheckedAgainstScope(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	rules, err := scope.New([]string{"127.0.0.1"}, []string{"path:^/private"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Scope = rules
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(cfg, printer, common.Target{URL: server.URL + "/admin"}, nil, "")
+
+	if _, err := scanner.inspectRaw(http.MethodGet, server.URL+"/admin", nil, map[string]string{"X-Forwarded-Host": "internal.corp"}, nil); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected an out-of-scope host header to be refused, got %v", err)
+	}
+	// %u0061 is not a valid URL escape, so the path is checked as written
+	if _, err := scanner.inspectPath(server.URL + "/private%u0061"); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected an excluded raw path to be refused, got %v", err)
+	}
+	if _, err := scanner.inspectPath(server.URL + "/admin/..;/"); err != nil {
+		t.Fatalf("expected an in-scope raw path to be sent, got %v", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected 1 request to reach the server, got %d", got)
+	}
+}
+
+func TestRawAndRegularSendsStripTheSameReflectedValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(htmlPage("403 Forbidden", "Access to "+r.RequestURI+" for "+r.Header.Get("X-Original-URL")+" is denied.")))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(cfg, printer, common.Target{URL: server.URL + "/admin"}, nil, "")
+
+	base, err := scanner.inspectBase(server.URL+"/admin", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := scanner.inspectPath(server.URL + "/admin/..;/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := scanner.inspectBase(server.URL+"/admin/..;/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := scanner.inspectRaw(http.MethodGet, server.URL+"/admin/%2e", nil, map[string]string{"X-Original-URL": "/internal/admin"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, summary := range map[string]httpclient.ResponseSummary{"raw": raw, "regular": regular, "raw with header": header} {
+		if summary.NormalizedHash != base.NormalizedHash {
+			t.Fatalf("%s: expected the echoed payload to be ignored, got %q vs %q", name, summary.TextSignature, base.TextSignature)
+		}
+	}
+}
+
+func TestRawRequestsSendOneContentType(t *testing.T) {
+	contentTypes := make(chan []string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes <- r.Header.Values("Content-Type")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	cfg := config.Default()
+	cfg.Headers["content-type"] = "text/plain"
+	target := common.Target{URL: server.URL + "/admin", Method: http.MethodPost, Body: []byte(`{"id":1}`), ContentType: "application/json"}
+	scanner := NewScanner(cfg, printer, target, nil, "")
+
+	if _, err := scanner.inspectPath(server.URL + "/admin/..;/"); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-contentTypes; len(got) != 1 || got[0] != "application/json" {
+		t.Fatalf("expected the target's Content-Type once, got %q", got)
+	}
+}
+
+func TestHTTP09SendsBareRequestLineAndReadsBodyOnly(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			requestLine, _ := reader.ReadString('\n')
+			lines <- requestLine
+			if !strings.Contains(requestLine, " HTTP/") {
+				// HTTP/0.9: the body alone, ended by closing the connection
+				conn.Write([]byte(htmlPage("Admin", "Welcome to the admin console with user management.")))
+				conn.Close()
+				continue
+			}
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == "\r\n" {
+					break
+				}
+			}
+			body := htmlPage("403 Forbidden", "Directory access is forbidden.")
+			fmt.Fprintf(conn, "HTTP/1.1 403 Forbidden\r\nContent-Type: text/html\r\nContent-Length: %d\r\nConnection: close\r\n\r\n%s", len(body), body)
+			conn.Close()
+		}
+	}()
+
+	base := "http://" + listener.Addr().String()
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(config.Default(), printer, common.Target{URL: base + "/admin"}, nil, "")
+	scanner.defaultRequest()
+	<-lines
+	<-lines
+
+	summary, err := scanner.requestHTTPVersion("0.9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-lines; got != "GET /admin\r\n" {
+		t.Fatalf("expected a bare HTTP/0.9 request line, got %q", got)
+	}
+	if summary.StatusCode != http.StatusForbidden || summary.Title != "Admin" {
+		t.Fatalf("expected the body-only reply with the default status, got %+v", summary)
+	}
+	if !protocolAccepted("0.9", summary) || !scanner.decideCandidate(summary).interesting {
+		t.Fatalf("expected the 0.9 reply to be compared by its body, got %+v", scanner.decideCandidate(summary))
+	}
+}
@@ -0,0 +1,147 @@
+package bypass
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// encodeUnicode returns the alternative spellings of one ASCII character that
+// normalizing servers and decoders may fold back into the original: the
+// fullwidth form, overlong UTF-8, IIS %u encoding and plain single encoding,
+// always in that order.
+func encodeUnicode(c byte) []string {
+	variants := make([]string, 0, 4)
+	if c > ' ' && c < 0x7f {
+		// U+FF01..U+FF5E mirror ASCII 0x21..0x7E
+		variants = append(variants, percentEncode(string(rune(c)+0xFEE0)))
+	}
+	variants = append(variants,
+		fmt.Sprintf("%%%02X%%%02X", 0xC0|c>>6, 0x80|c&0x3F),
+		fmt.Sprintf("%%u%04X", c),
+		fmt.Sprintf("%%%02X", c),
+	)
+	return variants
+}
+
+// percentEncode escapes every byte of s
+func percentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		fmt.Fprintf(&b, "%%%02X", s[i])
+	}
+	return b.String()
+}
+
+// unicodeSegments are the dot, dot-dot and slash segments spelled with the
+// homoglyphs of encodeUnicode, leaving out the plain encoding that the path
+// techniques already send
+func unicodeSegments() []string {
+	var segments []string
+	dots := encodeUnicode('.')
+	for _, dot := range dots[:len(dots)-1] {
+		segments = append(segments, dot+"/", dot+dot+"/")
+	}
+	slashes := encodeUnicode('/')
+	return append(segments, slashes[:len(slashes)-1]...)
+}
+
+// unicodePaths rewrites one character of path at a time with encodeUnicode,
+// inserts the segments of unicodeSegments after every slash and appends null
+// and whitespace bytes to the end of every segment. The leading slash is kept
+// so the request target stays valid.
+func unicodePaths(path string) []string {
+	if path == "/" {
+		return nil
+	}
+	seen := make(map[string]struct{})
+	var paths []string
+	add := func(modified string) {
+		if _, ok := seen[modified]; ok || modified == path {
+			return
+		}
+		seen[modified] = struct{}{}
+		paths = append(paths, modified)
+	}
+
+	for i, c := range path {
+		if c > 0x7e || (i == 0 && c == '/') {
+			continue
+		}
+		for _, encoded := range encodeUnicode(byte(c)) {
+			add(path[:i] + encoded + path[i+1:])
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		if path[i] != '/' {
+			continue
+		}
+		for _, segment := range unicodeSegments() {
+			add(path[:i+1] + segment + path[i+1:])
+		}
+	}
+
+	for i := 1; i <= len(path); i++ {
+		if i < len(path) && path[i] != '/' {
+			continue
+		}
+		if path[i-1] == '/' {
+			continue
+		}
+		for _, suffix := range []string{"%00", "%20", "%09"} {
+			add(path[:i] + suffix + path[i:])
+		}
+	}
+	return paths
+}
+
+func (s *Scanner) unicodeEncoding() {
+	s.printer.SectionHeader("UNICODE ENCODING")
+
+	parsedURL, err := url.Parse(s.targetURL)
+	if err != nil {
+		s.printer.Error("Error parsing URL: %v", err)
+		return
+	}
+
+	originalPath := parsedURL.Path
+	if originalPath == "" || originalPath == "/" {
+		s.printer.Info("No path to modify for unicode encoding")
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.config.Concurrency)
+
+	for _, modifiedPath := range unicodePaths(originalPath) {
+		encodedURI := fmt.Sprintf("%s://%s%s", parsedURL.Scheme, parsedURL.Host, modifiedPath)
+		if parsedURL.RawQuery != "" {
+			encodedURI += "?" + parsedURL.RawQuery
+		}
+
+		s.progress.AddTotal(1)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(uri, payload string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			summary, err := s.inspectPath(uri)
+			if err != nil {
+				return
+			}
+
+			decision := s.decideCandidate(summary)
+			if !decision.interesting {
+				s.logSuppressed("unicode", uri, s.requestMethod(), decision.suppressedReason)
+				return
+			}
+
+			s.emitBypassResult(uri, s.requestMethod(), "unicode", payload, nil, summary, decision)
+		}(encodedURI, modifiedPath)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,54 @@
+package bypass
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEncodeUnicode(t *testing.T) {
+	for _, tc := range []struct {
+		c    byte
+		want []string
+	}{
+		{'.', []string{"%EF%BC%8E", "%C0%AE", "%u002E", "%2E"}},
+		{'/', []string{"%EF%BC%8F", "%C0%AF", "%u002F", "%2F"}},
+		{'a', []string{"%EF%BD%81", "%C1%A1", "%u0061", "%61"}},
+	} {
+		if got := encodeUnicode(tc.c); !slices.Equal(got, tc.want) {
+			t.Fatalf("encodeUnicode(%q) = %v, want %v", tc.c, got, tc.want)
+		}
+	}
+}
+
+func TestUnicodePaths(t *testing.T) {
+	paths := unicodePaths("/api/admin")
+	for _, want := range []string{
+		"/%EF%BD%81pi/admin",
+		"/api%EF%BC%8Fadmin",
+		"/api%C0%AFadmin",
+		"/api/%u0061dmin",
+		"/api%00/admin",
+		"/api/admin%20",
+		"/api/admin%09",
+		"/%C0%AE%C0%AE/api/admin",
+		"/api/%EF%BC%8E%EF%BC%8E/admin",
+		"/api/%u002E%u002E/admin",
+		"/api/%C0%AE/admin",
+		"/api/%EF%BC%8Fadmin",
+	} {
+		if !slices.Contains(paths, want) {
+			t.Fatalf("expected %q in %v", want, paths)
+		}
+	}
+	for _, path := range paths {
+		if path[0] != '/' {
+			t.Fatalf("leading slash was rewritten: %q", path)
+		}
+	}
+	if slices.Contains(paths, "/api/%2E%2E/admin") {
+		t.Fatal("expected plain encoded segments to be left to the path techniques")
+	}
+	if len(unicodePaths("/")) != 0 {
+		t.Fatal("expected no variants for the root path")
+	}
+}
@@ -0,0 +1,112 @@
+package bypass
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+)
+
+// Outcomes of replaying a finding
+const (
+	VerifyStable = "stable"
+	VerifyFlaky  = "flaky"
+	VerifyGone   = "gone"
+
+	// VerifyInconclusive means no replay got a response
+	VerifyInconclusive = "inconclusive"
+)
+
+// Verification is the outcome of replaying a finding several times
+type Verification struct {
+	Status  string
+	Hits    int
+	Replays int
+
+	// Errors counts the replays that got no response, which are left out
+	// of the outcome
+	Errors int
+
+	// Last is the last response that still differed from the baselines, or
+	// the last response when none did
+	Last httpclient.ResponseSummary
+}
+
+// Verify collects fresh baselines for the scanner's target, then replays
+// finding n times. Of the replays that got a response, it is stable when
+// every one still differs from the baselines, flaky when only some do, and
+// gone when none do. It is inconclusive when no replay got a response.
+func (s *Scanner) Verify(finding common.ScanResult, n int) (Verification, error) {
+	s.calibrate()
+	summary, err := s.inspectBase(s.targetURL, nil)
+	if err != nil {
+		return Verification{}, fmt.Errorf("default request failed: %w", err)
+	}
+	s.defaultBody = s.learnDynamicMask(summary)
+
+	v := Verification{Replays: n}
+	for range n {
+		summary, err := s.replay(finding)
+		if err != nil {
+			v.Errors++
+			continue
+		}
+		if s.decideCandidate(summary).interesting {
+			v.Hits++
+			v.Last = summary
+		} else if v.Hits == 0 {
+			v.Last = summary
+		}
+	}
+
+	responses := n - v.Errors
+	switch {
+	case responses == 0:
+		v.Status = VerifyInconclusive
+	case v.Hits == responses:
+		v.Status = VerifyStable
+	case v.Hits == 0:
+		v.Status = VerifyGone
+	default:
+		v.Status = VerifyFlaky
+	}
+	return v, nil
+}
+
+// replay sends the exact request behind finding. Its method, headers and
+// request target are applied to the scanner's base request, which is the
+// finding's Base, like the parts of a chain.
+func (s *Scanner) replay(finding common.ScanResult) (httpclient.ResponseSummary, error) {
+	switch finding.Technique {
+	case "http version":
+		if finding.Payload == "HTTP/2" {
+			return s.requestHTTP2()
+		}
+		return s.requestHTTPVersion(strings.TrimPrefix(finding.Payload, "HTTP/"))
+	case "target form":
+		origin, target, err := splitRawURL(s.targetURL)
+		if err != nil {
+			return httpclient.ResponseSummary{}, err
+		}
+		for _, v := range targetFormVariants(origin, target) {
+			if v.label == finding.Payload {
+				return s.requestTargetForm(v)
+			}
+		}
+		return httpclient.ResponseSummary{}, fmt.Errorf("unknown request-target form %q", finding.Payload)
+	}
+
+	var chain []mutation
+	if finding.Method != "" && finding.Method != s.requestMethod() {
+		chain = append(chain, mutation{kind: mutationVerb, method: finding.Method})
+	}
+	for key, value := range finding.Headers {
+		chain = append(chain, mutation{kind: mutationHeader, header: [2]string{key, value}})
+	}
+	if finding.URL != s.targetURL {
+		chain = append(chain, mutation{kind: mutationPath, url: finding.URL})
+	}
+	_, _, summary, err := s.inspectChain(chain)
+	return summary, err
+}
@@ -0,0 +1,118 @@
+package bypass
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/output"
+)
+
+func TestVerifyReportsStableFlakyAndGoneFindings(t *testing.T) {
+	var flaky atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		admin := htmlPage("Admin", "Welcome to the admin dashboard with user management.")
+		switch {
+		case r.Header.Get("X-Original-URL") == "/admin":
+			w.Write([]byte(admin))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/.":
+			if flaky.Add(1)%2 == 0 {
+				w.Write([]byte(admin))
+				return
+			}
+			fallthrough
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(htmlPage("403 Forbidden", "Directory access is forbidden.")))
+		}
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	target := server.URL + "/admin"
+
+	for _, tc := range []struct {
+		name    string
+		finding common.ScanResult
+		want    string
+	}{
+		{"header", common.ScanResult{URL: target, Target: target, Method: "GET", Technique: "header bypass",
+			Payload: "X-Original-URL: /admin", Headers: map[string]string{"X-Original-URL": "/admin"}}, VerifyStable},
+		{"verb and path", common.ScanResult{URL: target + "/.", Target: target, Method: "POST", Technique: "chain",
+			Payload: "verb tampering: POST + endpath: /."}, VerifyFlaky},
+		{"path", common.ScanResult{URL: target + "/..;/", Target: target, Method: "GET", Technique: "endpath", Payload: "/..;/"}, VerifyGone},
+	} {
+		scanner := NewScanner(config.Default(), printer, common.Target{URL: target}, nil, "")
+		v, err := scanner.Verify(tc.finding, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Status != tc.want {
+			t.Fatalf("%s: expected %s, got %+v", tc.name, tc.want, v)
+		}
+	}
+}
+
+func TestVerifyReplaysTheRecordedBaseRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) == `{"id":1}` && r.Header.Get("Content-Type") == "application/json" &&
+			r.Header.Get("X-Api-Key") == "secret" && r.Header.Get("X-Original-URL") == "/admin + /" {
+			w.Write([]byte(htmlPage("Admin", "Welcome to the admin dashboard with user management.")))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(htmlPage("403 Forbidden", "Directory access is forbidden.")))
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	target := server.URL + "/admin"
+	// The header value contains the separator of chain labels
+	finding := common.ScanResult{URL: target, Target: target, Method: "POST", Technique: "chain",
+		Payload: "header bypass: X-Original-URL: /admin + /", Headers: map[string]string{"X-Original-URL": "/admin + /"},
+		Base: &common.Request{Method: "POST", Headers: map[string]string{"X-Api-Key": "secret"}, Body: []byte(`{"id":1}`), ContentType: "application/json"}}
+
+	v, err := NewScanner(config.Default(), printer, finding.BaseTarget(), nil, "").Verify(finding, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Status != VerifyStable {
+		t.Fatalf("expected the recorded request to be replayed, got %+v", v)
+	}
+}
+
+func TestVerifyIsInconclusiveWhenReplaysFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Original-URL") != "" {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(htmlPage("403 Forbidden", "Directory access is forbidden.")))
+	}))
+	defer server.Close()
+
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	target := server.URL + "/admin"
+	finding := common.ScanResult{URL: target, Target: target, Method: "GET", Technique: "header bypass",
+		Payload: "X-Original-URL: /admin", Headers: map[string]string{"X-Original-URL": "/admin"}}
+
+	v, err := NewScanner(config.Default(), printer, finding.BaseTarget(), nil, "").Verify(finding, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Status != VerifyInconclusive || v.Errors != 3 || v.Hits != 0 {
+		t.Fatalf("expected dropped connections to be inconclusive, got %+v", v)
+	}
+}
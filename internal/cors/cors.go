@@ -9,17 +9,19 @@ import (
 	"sync"
 
 	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
 	"github.com/aether-0/httpsuite/pkg/httpclient"
 	"github.com/aether-0/httpsuite/pkg/output"
 )
 
 // Scanner performs CORS misconfiguration testing
 type Scanner struct {
-	config   *common.Config
+	config   *config.Config
 	printer  *output.Printer
 	client   *httpclient.Client
 	origin   string
 	deepScan bool
+	progress *output.Progress
 }
 
 type originPayload struct {
@@ -28,15 +30,18 @@ type originPayload struct {
 }
 
 // NewScanner creates a new CORS scanner
-func NewScanner(cfg *common.Config, printer *output.Printer, origin string, deepScan bool) *Scanner {
+func NewScanner(cfg *config.Config, printer *output.Printer, origin string, deepScan bool) *Scanner {
 	client := httpclient.New(httpclient.Options{
-		Timeout:   cfg.Timeout,
-		Proxy:     cfg.Proxy,
-		UserAgent: cfg.UserAgent,
-		Headers:   cfg.Headers,
-		Retries:   cfg.Retries,
-		Redirect:  false,
-		Insecure:  true,
+		Timeout:     cfg.Timeout,
+		Proxy:       cfg.Proxy,
+		UserAgent:   cfg.UserAgent,
+		Headers:     cfg.Headers,
+		Retries:     cfg.Retries,
+		Redirect:    false,
+		Insecure:    true,
+		Session:     cfg.Session,
+		Scope:       cfg.Scope,
+		ContentType: cfg.ContentType,
 	})
 
 	return &Scanner{
@@ -50,44 +55,59 @@ func NewScanner(cfg *common.Config, printer *output.Printer, origin string, deep
 
 // Run executes the CORS scan across all targets
 func (s *Scanner) Run() {
-	s.printer.Info("Starting CORS misconfiguration scan for %d target(s)", len(s.config.URLs))
+	targets := s.config.ScanTargets()
+	s.printer.Info("Starting CORS misconfiguration scan for %d target(s)", len(targets))
+
+	s.progress = s.printer.StartProgress("cors")
+	defer s.progress.Finish()
 
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
-	for _, targetURL := range s.config.URLs {
-		s.preflightCheck(targetURL)
+	for _, target := range targets {
+		payloads := s.generatePayloads(target.URL)
+		s.progress.AddTotal(len(payloads) + 1)
+
+		s.preflightCheck(target)
 
-		for _, payload := range s.generatePayloads(targetURL) {
+		for _, payload := range payloads {
 			wg.Add(1)
 			sem <- struct{}{}
-			go func(targetURL string, payload originPayload) {
+			go func(target common.Target, payload originPayload) {
 				defer wg.Done()
 				defer func() { <-sem }()
-				s.testOrigin(targetURL, payload)
-			}(targetURL, payload)
+				s.testOrigin(target, payload)
+			}(target, payload)
 		}
 	}
 	wg.Wait()
 }
 
-func (s *Scanner) preflightCheck(targetURL string) {
+func (s *Scanner) preflightCheck(target common.Target) {
+	targetURL := target.URL
 	req, err := http.NewRequest("OPTIONS", targetURL, nil)
 	if err != nil {
 		s.printer.Error("Preflight error for %s: %v", targetURL, err)
 		return
 	}
 
-	req.Header.Set("Origin", s.origin)
 	req.Header.Set("User-Agent", s.config.UserAgent)
 	req.Header.Set("Accept", "*/*")
 	req.Header.Set("Connection", "close")
-
 	for k, v := range s.config.Headers {
 		req.Header.Set(k, v)
 	}
 
+	// A request file may carry its own Origin and preflight headers; the
+	// probe's values win
+	req.Header.Set("Origin", s.origin)
+	req.Header.Set("Access-Control-Request-Method", target.Method)
+	if len(target.Body) > 0 {
+		req.Header.Set("Access-Control-Request-Headers", "content-type")
+	}
+
 	resp, err := s.client.Do(req)
+	s.progress.Request(err)
 	if err != nil {
 		s.printer.Error("Preflight request failed for %s: %v", targetURL, err)
 		return
@@ -106,13 +126,13 @@ func (s *Scanner) preflightCheck(targetURL string) {
 	}
 }
 
-func (s *Scanner) testOrigin(targetURL string, payload originPayload) {
-	req, err := http.NewRequest(s.config.Method, targetURL, nil)
+func (s *Scanner) testOrigin(target common.Target, payload originPayload) {
+	targetURL := target.URL
+	req, err := httpclient.NewRequest(target.Method, targetURL, target.Body)
 	if err != nil {
 		return
 	}
 
-	req.Header.Set("Origin", payload.value)
 	req.Header.Set("User-Agent", s.config.UserAgent)
 	req.Header.Set("Accept", "*/*")
 	req.Header.Set("Accept-Language", "en")
@@ -121,8 +141,14 @@ func (s *Scanner) testOrigin(targetURL string, payload originPayload) {
 	for k, v := range s.config.Headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range target.RequestHeaders() {
+		req.Header.Set(k, v)
+	}
+	// Imported requests often carry the app's own Origin; the payload wins
+	req.Header.Set("Origin", payload.value)
 
 	resp, err := s.client.Do(req)
+	s.progress.Request(err)
 	if err != nil {
 		return
 	}
@@ -133,24 +159,28 @@ func (s *Scanner) testOrigin(targetURL string, payload originPayload) {
 	acac := resp.Header.Get("Access-Control-Allow-Credentials")
 	vary := resp.Header.Get("Vary")
 
-	vulnerable, details := evaluateResponse(payload, acao, acac, vary)
+	vulnerable, findings := evaluateResponse(payload, acao, acac, vary)
 	if vulnerable {
-		for _, detail := range details {
+		for _, f := range findings {
 			s.printer.Result(common.ScanResult{
 				URL:        targetURL,
-				Method:     s.config.Method,
+				Method:     target.Method,
 				StatusCode: resp.StatusCode,
 				Module:     "cors",
-				Detail:     fmt.Sprintf("Origin: %s → %s", payload.value, detail),
+				Target:     targetURL,
+				Detail:     fmt.Sprintf("Origin: %s → %s", payload.value, f.detail),
 				Vulnerable: true,
+				Severity:   f.severity,
+				Confidence: f.confidence,
 			})
 		}
 	} else if s.config.Verbose {
 		s.printer.Result(common.ScanResult{
 			URL:        targetURL,
-			Method:     s.config.Method,
+			Method:     target.Method,
 			StatusCode: resp.StatusCode,
 			Module:     "cors",
+			Target:     targetURL,
 			Detail:     fmt.Sprintf("Origin: %s → not vulnerable", payload.value),
 			Vulnerable: false,
 		})
@@ -213,62 +243,99 @@ func (s *Scanner) generatePayloads(targetURL string) []originPayload {
 	return dedupeOriginPayloads(payloads)
 }
 
-// evaluateResponse checks for CORS misconfigurations
-func evaluateResponse(payload originPayload, acao, acac, vary string) (bool, []string) {
-	var details []string
+// finding is one misconfiguration with its score
+type finding struct {
+	detail     string
+	severity   string
+	confidence string
+}
+
+// originSeverity scores an accepted attacker origin by how hard it is to
+// obtain and whether the browser sends credentials with it.
+var originSeverity = map[string][2]string{
+	// category: {without credentials, with credentials}
+	"reflection":         {common.SeverityMedium, common.SeverityCritical},
+	"custom":             {common.SeverityMedium, common.SeverityCritical},
+	"alternate-port":     {common.SeverityMedium, common.SeverityCritical},
+	"developer-backdoor": {common.SeverityLow, common.SeverityHigh},
+	"predomain":          {common.SeverityLow, common.SeverityHigh},
+	"postdomain":         {common.SeverityLow, common.SeverityHigh},
+	"subdomain":          {common.SeverityLow, common.SeverityMedium},
+	"non-ssl":            {common.SeverityLow, common.SeverityMedium},
+}
+
+// evaluateResponse checks for CORS misconfigurations and scores each one.
+// Credentials make an accepted origin exploitable, so they raise severity.
+func evaluateResponse(payload originPayload, acao, acac, vary string) (bool, []finding) {
+	var findings []finding
+	add := func(detail, severity, confidence string) {
+		findings = append(findings, finding{detail: detail, severity: severity, confidence: confidence})
+	}
+
 	creds := strings.EqualFold(acac, "true")
 	credSuffix := ""
+	credIndex := 0
 	if creds {
 		credSuffix = " (with credentials)"
+		credIndex = 1
 	}
 
 	if strings.ContainsAny(acao, ",|") || (strings.Contains(acao, " ") && acao != "null") {
-		details = append(details, "Invalid ACAO header formatting")
+		add("Invalid ACAO header formatting", common.SeverityInfo, common.ConfidenceFirm)
 	}
 
 	if strings.Contains(acao, "*.") {
-		details = append(details, "Invalid wildcard use in ACAO")
+		add("Invalid wildcard use in ACAO", common.SeverityLow, common.ConfidenceTentative)
 	}
 
 	if acao == "*" {
-		details = append(details, "Wildcard ACAO: *"+credSuffix)
+		// Browsers refuse credentials with a wildcard, so only public data leaks
+		add("Wildcard ACAO: *"+credSuffix, common.SeverityLow, common.ConfidenceCertain)
 	}
 
 	if payload.value == "null" && acao == "null" {
-		details = append(details, "Null misconfiguration"+credSuffix)
+		severity := common.SeverityLow
+		if creds {
+			severity = common.SeverityHigh
+		}
+		add("Null misconfiguration"+credSuffix, severity, common.ConfidenceCertain)
 	}
 
 	if acao == payload.value {
+		severity := common.SeverityInfo
+		if levels, ok := originSeverity[payload.category]; ok {
+			severity = levels[credIndex]
+		}
 		switch payload.category {
 		case "developer-backdoor":
-			details = append(details, "Developer backdoor"+credSuffix)
+			add("Developer backdoor"+credSuffix, severity, common.ConfidenceCertain)
 		case "reflection":
-			details = append(details, "Origin reflection"+credSuffix)
+			add("Origin reflection"+credSuffix, severity, common.ConfidenceCertain)
 		case "predomain":
-			details = append(details, "Pre-domain wildcard"+credSuffix)
+			add("Pre-domain wildcard"+credSuffix, severity, common.ConfidenceCertain)
 		case "postdomain":
-			details = append(details, "Post-domain wildcard"+credSuffix)
+			add("Post-domain wildcard"+credSuffix, severity, common.ConfidenceCertain)
 		case "subdomain":
-			details = append(details, "Arbitrary subdomains allowed"+credSuffix)
+			add("Arbitrary subdomains allowed"+credSuffix, severity, common.ConfidenceCertain)
 		case "non-ssl":
-			details = append(details, "Non-ssl origin allowed"+credSuffix)
+			add("Non-ssl origin allowed"+credSuffix, severity, common.ConfidenceCertain)
 		case "alternate-port":
-			details = append(details, "Arbitrary origin/port reflection"+credSuffix)
+			add("Arbitrary origin/port reflection"+credSuffix, severity, common.ConfidenceCertain)
 		case "custom":
-			details = append(details, "Custom origin reflected"+credSuffix)
+			add("Custom origin reflected"+credSuffix, severity, common.ConfidenceCertain)
 		}
 
 		if creds && payload.category != "same-origin" && payload.category != "null" {
-			details = append(details, fmt.Sprintf("Credentials allowed with reflected origin: ACAO=%s, ACAC=true", acao))
+			add(fmt.Sprintf("Credentials allowed with reflected origin: ACAO=%s, ACAC=true", acao), severity, common.ConfidenceCertain)
 		}
 	}
 
 	if strings.Contains(vary, "Origin") && acao != "" && creds {
-		details = append(details, "ACAO dynamically varies on Origin with credentials")
+		add("ACAO dynamically varies on Origin with credentials", common.SeverityInfo, common.ConfidenceFirm)
 	}
 
-	if len(details) > 0 {
-		return true, details
+	if len(findings) > 0 {
+		return true, findings
 	}
 	return false, nil
 }
@@ -1,8 +1,17 @@
 package cors
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/importer"
+	"github.com/aether-0/httpsuite/pkg/output"
 )
 
 func TestGeneratePayloadsUsesOriginOnly(t *testing.T) {
@@ -63,11 +72,99 @@ func TestEvaluateResponsePreDomainWildcard(t *testing.T) {
 	}
 }
 
-func containsDetail(details []string, want string) bool {
-	for _, detail := range details {
-		if strings.Contains(detail, want) {
+func TestEvaluateResponseScoresCredentials(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		payload  originPayload
+		acao     string
+		acac     string
+		severity string
+	}{
+		{"wildcard", originPayload{value: "https://evil.com", category: "reflection"}, "*", "", common.SeverityLow},
+		{"reflection", originPayload{value: "https://evil.com", category: "reflection"}, "https://evil.com", "", common.SeverityMedium},
+		{"credentialed reflection", originPayload{value: "https://evil.com", category: "reflection"}, "https://evil.com", "true", common.SeverityCritical},
+		{"credentialed null", originPayload{value: "null", category: "null"}, "null", "true", common.SeverityHigh},
+		{"subdomain", originPayload{value: "https://a.example.com", category: "subdomain"}, "https://a.example.com", "", common.SeverityLow},
+	} {
+		_, findings := evaluateResponse(tc.payload, tc.acao, tc.acac, "")
+		if len(findings) == 0 {
+			t.Fatalf("%s: expected a finding", tc.name)
+		}
+		if findings[0].severity != tc.severity || findings[0].confidence == "" {
+			t.Fatalf("%s: expected %s, got %+v", tc.name, tc.severity, findings[0])
+		}
+	}
+}
+
+func containsDetail(findings []finding, want string) bool {
+	for _, f := range findings {
+		if strings.Contains(f.detail, want) {
 			return true
 		}
 	}
 	return false
 }
+
+func TestTestOriginOverridesImportedOrigin(t *testing.T) {
+	origins := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origins <- r.Header.Get("Origin")
+		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+	}))
+	defer server.Close()
+
+	harPath := filepath.Join(t.TempDir(), "capture.har")
+	har := `{"log":{"entries":[{"request":{"method":"POST","url":"` + server.URL + `/api",
+ "headers":[{"name":"Origin","value":"https://app.example.com"}],
+ "postData":{"mimeType":"application/json","text":"{}"}}}]}}`
+	if err := os.WriteFile(harPath, []byte(har), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	targets, err := importer.Load(harPath, importer.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 1 || targets[0].Headers["Origin"] == "" {
+		t.Fatalf("expected one imported target with an Origin header, got %+v", targets)
+	}
+
+	cfg := config.Default()
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(cfg, printer, "https://evil.com", false)
+	scanner.testOrigin(cfg.ResolveTarget(targets[0]), originPayload{value: "https://evil.com", category: "reflection"})
+
+	if got := <-origins; got != "https://evil.com" {
+		t.Fatalf("expected the payload Origin to be sent, got %q", got)
+	}
+}
+
+func TestPreflightOverridesRequestFileOrigin(t *testing.T) {
+	requests := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r.Header.Clone()
+	}))
+	defer server.Close()
+
+	// Headers of a saved browser request, as applied by -r
+	cfg := config.Default()
+	cfg.Headers = map[string]string{
+		"Origin":                        "https://app.example.com",
+		"Access-Control-Request-Method": "PUT",
+	}
+	printer := output.NewPrinter(true, true, false, "")
+	defer printer.Close()
+	scanner := NewScanner(cfg, printer, "https://evil.com", false)
+	target := cfg.ResolveTarget(common.Target{URL: server.URL + "/api"})
+
+	scanner.preflightCheck(target)
+	preflight := <-requests
+	if preflight.Get("Origin") != "https://evil.com" || preflight.Get("Access-Control-Request-Method") != target.Method {
+		t.Fatalf("expected the probe's preflight headers, got %v", preflight)
+	}
+
+	scanner.testOrigin(target, originPayload{value: "null", category: "null"})
+	if got := (<-requests).Get("Origin"); got != "null" {
+		t.Fatalf("expected the payload Origin to be sent, got %q", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
 	"github.com/aether-0/httpsuite/pkg/httpclient"
 	"github.com/aether-0/httpsuite/pkg/output"
 )
@@ -64,21 +65,24 @@ var appendList = []string{
 
 // Scanner performs CRLF injection testing
 type Scanner struct {
-	config  *common.Config
+	config  *config.Config
 	printer *output.Printer
 	client  *httpclient.Client
 }
 
 // NewScanner creates a new CRLF scanner
-func NewScanner(cfg *common.Config, printer *output.Printer) *Scanner {
+func NewScanner(cfg *config.Config, printer *output.Printer) *Scanner {
 	client := httpclient.New(httpclient.Options{
-		Timeout:   cfg.Timeout,
-		Proxy:     cfg.Proxy,
-		UserAgent: cfg.UserAgent,
-		Headers:   cfg.Headers,
-		Retries:   cfg.Retries,
-		Redirect:  false, // Don't follow redirects for CRLF testing
-		Insecure:  true,
+		Timeout:     cfg.Timeout,
+		Proxy:       cfg.Proxy,
+		UserAgent:   cfg.UserAgent,
+		Headers:     cfg.Headers,
+		Retries:     cfg.Retries,
+		Redirect:    false, // Don't follow redirects for CRLF testing
+		Insecure:    true,
+		Session:     cfg.Session,
+		Scope:       cfg.Scope,
+		ContentType: cfg.ContentType,
 	})
 
 	return &Scanner{
@@ -110,23 +114,29 @@ func GenerateURLs(baseURL string) []string {
 
 // Run executes the CRLF scan across all target URLs
 func (s *Scanner) Run() {
-	s.printer.Info("Starting CRLF injection scan for %d target(s)", len(s.config.URLs))
+	targets := s.config.ScanTargets()
+	s.printer.Info("Starting CRLF injection scan for %d target(s)", len(targets))
+
+	progress := s.printer.StartProgress("crlf")
+	defer progress.Finish()
 
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
-	for _, targetURL := range s.config.URLs {
-		testURLs := GenerateURLs(targetURL)
-		s.printer.Info("Testing %d CRLF payloads against %s", len(testURLs), targetURL)
+	for _, target := range targets {
+		testURLs := GenerateURLs(target.URL)
+		s.printer.Info("Testing %d CRLF payloads against %s", len(testURLs), target.URL)
+		progress.AddTotal(len(testURLs))
 
 		for _, testURL := range testURLs {
 			wg.Add(1)
 			sem <- struct{}{}
-			go func(target, testURL string) {
+			go func(target common.Target, testURL string) {
 				defer wg.Done()
 				defer func() { <-sem }()
 
-				vulnerable, statusCode, err := s.scan(testURL)
+				vulnerable, statusCode, err := s.scan(target, testURL)
+				progress.Request(err)
 				if err != nil {
 					if s.config.Verbose {
 						s.printer.Error("CRLF test error for %s: %v", testURL, err)
@@ -137,31 +147,35 @@ func (s *Scanner) Run() {
 				if vulnerable {
 					s.printer.Result(common.ScanResult{
 						URL:        testURL,
-						Method:     s.config.Method,
+						Method:     target.Method,
 						StatusCode: statusCode,
 						Module:     "crlf",
+						Target:     target.URL,
 						Detail:     "CRLF injection detected - injected header reflected",
 						Vulnerable: true,
+						Severity:   common.SeverityHigh,
+						Confidence: common.ConfidenceFirm,
 					})
 				} else if s.config.Verbose {
 					s.printer.Result(common.ScanResult{
 						URL:        testURL,
-						Method:     s.config.Method,
+						Method:     target.Method,
 						StatusCode: statusCode,
 						Module:     "crlf",
+						Target:     target.URL,
 						Detail:     "not vulnerable",
 						Vulnerable: false,
 					})
 				}
-			}(targetURL, testURL)
+			}(target, testURL)
 		}
 	}
 	wg.Wait()
 }
 
-// scan tests a single URL for CRLF injection
-func (s *Scanner) scan(testURL string) (bool, int, error) {
-	req, err := http.NewRequest(s.config.Method, testURL, nil)
+// scan tests a single URL for CRLF injection with the target's base request
+func (s *Scanner) scan(target common.Target, testURL string) (bool, int, error) {
+	req, err := httpclient.NewRequest(target.Method, testURL, target.Body)
 	if err != nil {
 		return false, 0, fmt.Errorf("error creating request: %w", err)
 	}
@@ -170,6 +184,9 @@ func (s *Scanner) scan(testURL string) (bool, int, error) {
 	for k, v := range s.config.Headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range target.RequestHeaders() {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
 	"github.com/aether-0/httpsuite/pkg/httpclient"
 	"github.com/aether-0/httpsuite/pkg/output"
 	"github.com/aether-0/httpsuite/pkg/utils"
@@ -25,7 +26,7 @@ var defaultMethods = []string{
 
 // Scanner performs HTTP method testing
 type Scanner struct {
-	config       *common.Config
+	config       *config.Config
 	printer      *output.Printer
 	client       *httpclient.Client
 	methods      []string
@@ -33,15 +34,18 @@ type Scanner struct {
 }
 
 // NewScanner creates a new methods scanner
-func NewScanner(cfg *common.Config, printer *output.Printer, methodList string, filterStatus string) *Scanner {
+func NewScanner(cfg *config.Config, printer *output.Printer, methodList string, filterStatus string) *Scanner {
 	client := httpclient.New(httpclient.Options{
-		Timeout:   cfg.Timeout,
-		Proxy:     cfg.Proxy,
-		UserAgent: cfg.UserAgent,
-		Headers:   cfg.Headers,
-		Retries:   cfg.Retries,
-		Redirect:  cfg.Redirect,
-		Insecure:  true,
+		Timeout:     cfg.Timeout,
+		Proxy:       cfg.Proxy,
+		UserAgent:   cfg.UserAgent,
+		Headers:     cfg.Headers,
+		Retries:     cfg.Retries,
+		Redirect:    cfg.Redirect,
+		Insecure:    true,
+		Session:     cfg.Session,
+		Scope:       cfg.Scope,
+		ContentType: cfg.ContentType,
 	})
 
 	methods := defaultMethods
@@ -82,22 +86,36 @@ func NewScanner(cfg *common.Config, printer *output.Printer, methodList string,
 
 // Run executes the HTTP method scan against all targets
 func (s *Scanner) Run() {
+	targets := uniqueURLTargets(s.config.ScanTargets())
 	s.printer.Info("Starting HTTP method scan for %d target(s) with %d methods",
-		len(s.config.URLs), len(s.methods))
+		len(targets), len(s.methods))
 	s.printer.Info("Methods: %s", strings.Join(s.methods, ", "))
 
+	progress := s.printer.StartProgress("methods")
+	defer progress.Finish()
+	progress.AddTotal(len(targets) * len(s.methods))
+
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, s.config.Concurrency)
 
-	for _, targetURL := range s.config.URLs {
+	for _, target := range targets {
 		for _, method := range s.methods {
 			wg.Add(1)
 			sem <- struct{}{}
-			go func(targetURL, method string) {
+			go func(target common.Target, method string) {
 				defer wg.Done()
 				defer func() { <-sem }()
 
-				statusCode, contentLength, err := s.client.SimpleRequest(method, targetURL, nil)
+				targetURL := target.URL
+				var body []byte
+				headers := target.Headers
+				if utils.MethodAllowsBody(method) {
+					body = target.Body
+					headers = target.RequestHeaders()
+				}
+				summary, err := s.client.InspectRequestBody(method, targetURL, headers, body)
+				progress.Request(err)
+				statusCode, contentLength := summary.StatusCode, summary.ContentLength
 				if err != nil {
 					if s.config.Verbose {
 						s.printer.Error("Error with %s [%s]: %v", targetURL, method, err)
@@ -113,12 +131,14 @@ func (s *Scanner) Run() {
 				// Determine if this looks interesting
 				vulnerable := false
 				detail := ""
+				severity, confidence := common.SeverityInfo, ""
 				switch {
 				case statusCode >= 200 && statusCode < 300:
 					detail = "success"
 					if method != "GET" && method != "HEAD" && method != "OPTIONS" {
 						vulnerable = true
 						detail = "unexpected success - method may be enabled"
+						severity, confidence = methodSeverity(method)
 					}
 				case statusCode == 405:
 					detail = "method not allowed"
@@ -134,11 +154,43 @@ func (s *Scanner) Run() {
 					StatusCode:    statusCode,
 					ContentLength: contentLength,
 					Module:        "methods",
+					Target:        targetURL,
 					Detail:        detail,
 					Vulnerable:    vulnerable,
+					Severity:      severity,
+					Confidence:    confidence,
 				})
-			}(targetURL, method)
+			}(target, method)
 		}
 	}
 	wg.Wait()
 }
+
+// methodSeverity scores a non-safe method that answered with success. Many
+// frameworks answer every method like GET, so only a manual check can confirm
+// that a state-changing method really works.
+func methodSeverity(method string) (string, string) {
+	switch strings.ToUpper(method) {
+	case "PUT", "DELETE", "PATCH", "MOVE", "COPY", "MKCOL", "PROPPATCH":
+		return common.SeverityMedium, common.ConfidenceTentative
+	case "TRACE", "TRACK":
+		return common.SeverityLow, common.ConfidenceFirm
+	default:
+		return common.SeverityLow, common.ConfidenceTentative
+	}
+}
+
+// uniqueURLTargets keeps the first target per URL, since every method is
+// tried against each URL anyway
+func uniqueURLTargets(targets []common.Target) []common.Target {
+	seen := make(map[string]bool, len(targets))
+	unique := make([]common.Target, 0, len(targets))
+	for _, t := range targets {
+		if seen[t.URL] {
+			continue
+		}
+		seen[t.URL] = true
+		unique = append(unique, t)
+	}
+	return unique
+}
@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/config"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
 	"github.com/aether-0/httpsuite/pkg/output"
 )
 
@@ -41,34 +43,42 @@ type Payload struct {
 
 // Scanner performs HTTP request smuggling testing via H2 downgrade
 type Scanner struct {
-	config        *common.Config
+	config        *config.Config
 	printer       *output.Printer
 	extended      bool
 	gadgetFile    string
 	detectTimeout int
+	client        *httpclient.Client
+	progress      *output.Progress
 }
 
 // NewScanner creates a new smuggle scanner
-func NewScanner(cfg *common.Config, printer *output.Printer, extended bool, gadgetFile string, detectTimeout int) *Scanner {
+func NewScanner(cfg *config.Config, printer *output.Printer, extended bool, gadgetFile string, detectTimeout int) *Scanner {
 	return &Scanner{
 		config:        cfg,
 		printer:       printer,
 		extended:      extended,
 		gadgetFile:    gadgetFile,
 		detectTimeout: detectTimeout,
+		client:        httpclient.New(httpclient.Options{Timeout: cfg.Timeout, Scope: cfg.Scope}),
 	}
 }
 
 // Run executes the smuggling scan
 func (s *Scanner) Run() {
-	s.printer.Info("Starting HTTP smuggling scan for %d target(s)", len(s.config.URLs))
+	targets := s.config.ScanTargets()
+	s.printer.Info("Starting HTTP smuggling scan for %d target(s)", len(targets))
 
-	for _, targetURL := range s.config.URLs {
-		s.scanTarget(targetURL)
+	s.progress = s.printer.StartProgress("smuggle")
+	defer s.progress.Finish()
+
+	for _, target := range targets {
+		s.scanTarget(target)
 	}
 }
 
-func (s *Scanner) scanTarget(targetURL string) {
+func (s *Scanner) scanTarget(target common.Target) {
+	targetURL := target.URL
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
 		s.printer.Error("Error parsing URL %s: %v", targetURL, err)
@@ -80,6 +90,15 @@ func (s *Scanner) scanTarget(targetURL string) {
 		return
 	}
 
+	if err := s.config.Scope.Check(parsedURL); err != nil {
+		s.printer.Warning("Skipping %s: %v", targetURL, err)
+		return
+	}
+	if info, ok := s.config.Preflight.Host(targetURL); ok && !info.HTTP2 {
+		s.printer.Info("Skipping %s: pre-flight found no HTTP/2 support", targetURL)
+		return
+	}
+
 	host := parsedURL.Hostname()
 	port := parsedURL.Port()
 	if port == "" {
@@ -98,8 +117,9 @@ func (s *Scanner) scanTarget(targetURL string) {
 		return
 	}
 	s.printer.Info("Loaded %d smuggling gadgets for %s", len(payloads), host)
+	s.progress.AddTotal(len(payloads))
 
-	method := strings.ToUpper(s.config.Method)
+	method := strings.ToUpper(target.Method)
 	if method == "" || method == "GET" {
 		method = "POST"
 	}
@@ -115,13 +135,21 @@ func (s *Scanner) scanTarget(targetURL string) {
 			defer func() { <-sem }()
 
 			result := s.testPayload(host, port, parsedURL.Scheme, parsedURL.Path, parsedURL.RawQuery, method, p)
+			if strings.Contains(result, "error") {
+				s.progress.Request(fmt.Errorf("%s", result))
+			} else {
+				s.progress.Request(nil)
+			}
 
 			vulnerable := false
 			detail := p.Name + " → " + result
+			severity, confidence := common.SeverityInfo, ""
 
 			if strings.Contains(result, "TIMEOUT") {
+				// A hang fits a desynced backend but also a slow or rate-limited one
 				vulnerable = true
 				detail = p.Name + " → TIMEOUT (potential smuggling)"
+				severity, confidence = common.SeverityMedium, common.ConfidenceTentative
 			} else if strings.Contains(result, "GOAWAY") {
 				detail = p.Name + " → GOAWAY"
 			} else if strings.Contains(result, "RST") {
@@ -132,8 +160,11 @@ func (s *Scanner) scanTarget(targetURL string) {
 				URL:        targetURL,
 				Method:     method,
 				Module:     "smuggle",
+				Target:     targetURL,
 				Detail:     detail,
 				Vulnerable: vulnerable,
+				Severity:   severity,
+				Confidence: confidence,
 			})
 		}(payload)
 	}
@@ -141,21 +172,17 @@ func (s *Scanner) scanTarget(targetURL string) {
 }
 
 func (s *Scanner) testPayload(host, port, scheme, path, query, method string, payload Payload) string {
-	addr := net.JoinHostPort(host, port)
-
-	conn, err := tls.DialWithDialer(
-		&net.Dialer{Timeout: time.Duration(s.config.Timeout.Seconds()) * time.Second},
-		"tcp",
-		addr,
-		&tls.Config{
-			InsecureSkipVerify: true,
-			NextProtos:         []string{"h2"},
-		},
-	)
+	target := &url.URL{Scheme: "https", Host: net.JoinHostPort(host, port), Path: path, RawQuery: query}
+
+	raw, err := s.client.Dial(target, &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2"},
+	})
 	if err != nil {
 		return fmt.Sprintf("connection error: %v", err)
 	}
-	defer conn.Close()
+	defer raw.Close()
+	conn := raw.(*tls.Conn)
 
 	// Check if h2 was negotiated
 	if conn.ConnectionState().NegotiatedProtocol != "h2" {
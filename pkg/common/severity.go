@@ -0,0 +1,50 @@
+package common
+
+// Severity levels, from least to most severe
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Confidence levels: tentative results need manual review, firm ones are
+// backed by a clear response difference, certain ones cannot be benign.
+const (
+	ConfidenceTentative = "tentative"
+	ConfidenceFirm      = "firm"
+	ConfidenceCertain   = "certain"
+)
+
+// Severities lists the severity levels from most to least severe
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// SeverityRank orders severities: info is 0, critical is 4, and an
+// unknown level is -1.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return len(Severities) - 1 - i
+		}
+	}
+	return -1
+}
+
+// IsSeverity reports whether name is a known severity level
+func IsSeverity(name string) bool {
+	return SeverityRank(name) >= 0
+}
+
+// EffectiveSeverity returns the result's severity, deriving one from
+// Vulnerable for results that were not scored.
+func (r ScanResult) EffectiveSeverity() string {
+	switch {
+	case r.Severity != "":
+		return r.Severity
+	case r.Vulnerable:
+		return SeverityHigh
+	default:
+		return SeverityInfo
+	}
+}
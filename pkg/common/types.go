@@ -1,12 +1,27 @@
 package common
 
 import (
-	"net/url"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 )
 
-// ScanResult represents the result of any scan module
+// Version is the httpsuite release reported by the CLI and in reports
+const Version = "1.0.0"
+
+// SchemaVersion is the version of the ScanResult JSON schema. Fields may be
+// added within a version; renaming, removing or retyping one bumps it.
+const SchemaVersion = 1
+
+// ScanResult represents the result of any scan module. Its JSON form is the
+// stable record written by -j and the JSON output files.
 type ScanResult struct {
+	SchemaVersion int       `json:"schema_version"`
+	ID            string    `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+
 	URL           string `json:"url"`
 	Method        string `json:"method,omitempty"`
 	StatusCode    int    `json:"status_code"`
@@ -17,46 +32,138 @@ type ScanResult struct {
 	Fingerprint   string `json:"fingerprint,omitempty"`
 	Module        string `json:"module"`
 	Vulnerable    bool   `json:"vulnerable"`
+	Severity      string `json:"severity"`
+	Confidence    string `json:"confidence,omitempty"`
+
+	// Target is the scanned URL the result was derived from; Technique and
+	// Payload say how it was derived
+	Target    string `json:"target,omitempty"`
+	Technique string `json:"technique,omitempty"`
+	Payload   string `json:"payload,omitempty"`
+
+	// Headers are the request headers the technique injected. With Method
+	// and URL, the raw request target, they are the mutation of Base.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Base is the request the technique mutated, so that the finding can be
+	// replayed exactly
+	Base *Request `json:"base,omitempty"`
+
+	// Verified is stable, flaky or gone on results of the verify command
+	Verified string `json:"verified,omitempty"`
+
+	// Count and Payloads summarize a cluster of results with the same
+	// response when --unique is set
+	Count    int      `json:"count,omitempty"`
+	Payloads []string `json:"payloads,omitempty"`
+
+	// Record is RecordCluster on the summary of a --unique cluster, which is
+	// written when the scan ends and has its own id. ClusterOf is the id of
+	// the finding it summarizes.
+	Record    string `json:"record,omitempty"`
+	ClusterOf string `json:"cluster_of,omitempty"`
+
+	// Host is set on pre-flight records
+	Host *Host `json:"host,omitempty"`
 }
 
-// Target represents a scan target
-type Target struct {
-	URL     string
-	Headers map[string]string
-	Method  string
+// Host is what the pre-flight phase learned about one origin
+type Host struct {
+	Origin         string `json:"origin"`
+	Alive          bool   `json:"alive"`
+	Error          string `json:"error,omitempty"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	TLS            bool   `json:"tls"`
+	TLSVersion     string `json:"tls_version,omitempty"`
+	HTTP2          bool   `json:"http2"`
+	Server         string `json:"server,omitempty"`
+	PoweredBy      string `json:"powered_by,omitempty"`
+	WAF            string `json:"waf,omitempty"`
+	Parked         bool   `json:"parked,omitempty"`
+	Wildcard404    bool   `json:"wildcard_404"`
+	NotFoundStatus int    `json:"not_found_status,omitempty"`
 }
 
-// Config holds global configuration shared across modules
-type Config struct {
+// Summary is a one-line description for terminal output
+func (h Host) Summary() string {
+	if !h.Alive {
+		return "dead: " + h.Error
+	}
+	parts := []string{}
+	if h.TLS {
+		parts = append(parts, h.TLSVersion)
+		if h.HTTP2 {
+			parts = append(parts, "h2")
+		} else {
+			parts = append(parts, "no h2")
+		}
+	}
+	if h.Server != "" {
+		parts = append(parts, "server="+h.Server)
+	}
+	if h.WAF != "" {
+		parts = append(parts, "waf="+h.WAF)
+	}
+	if h.Wildcard404 {
+		parts = append(parts, fmt.Sprintf("wildcard %d for missing paths", h.NotFoundStatus))
+	}
+	if h.Parked {
+		parts = append(parts, "parked")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Request is the base request of a bypass finding: the method, the target's
+// own headers and the body
+type Request struct {
+	Method      string            `json:"method"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+}
+
+// RecordCluster marks the summary record of a --unique cluster
+const RecordCluster = "cluster"
+
+// FindingID identifies a result by what was found rather than when, so the
+// same finding gets the same ID in every run.
+func (r ScanResult) FindingID() string {
+	key := strings.Join([]string{r.Module, r.Target, r.Method, r.URL, r.Detail}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// BaseTarget returns the target of the result with its recorded base
+// request. Results written without one get the configured request.
+func (r ScanResult) BaseTarget() Target {
+	t := Target{URL: r.Target}
+	if r.Base != nil {
+		t.Method = r.Base.Method
+		t.Headers = r.Base.Headers
+		t.Body = r.Base.Body
+		t.ContentType = r.Base.ContentType
+	}
+	return t
+}
+
+// Target represents a scan target
+type Target struct {
 	URL         string
-	URLs        []string
-	Method      string
-	PayloadDir  string
-	Concurrency int
-	Timeout     time.Duration
-	Retries     int
-	Proxy       *url.URL
-	ProxyStr    string
 	Headers     map[string]string
-	UserAgent   string
-	RandomAgent bool
-	Silent      bool
-	Verbose     bool
-	NoColor     bool
-	OutputFile  string
-	JSONOutput  bool
-	Redirect    bool
-}
-
-// DefaultConfig returns a config with sane defaults
-func DefaultConfig() *Config {
-	return &Config{
-		Method:      "GET",
-		PayloadDir:  "payloads",
-		Concurrency: 10,
-		Timeout:     10 * time.Second,
-		Retries:     1,
-		UserAgent:   "httpsuite/1.0",
-		Headers:     make(map[string]string),
+	Method      string
+	Body        []byte
+	ContentType string
+}
+
+// RequestHeaders returns the target's own headers plus its Content-Type when
+// it carries a body. Global headers are added by the HTTP client.
+func (t Target) RequestHeaders() map[string]string {
+	headers := make(map[string]string, len(t.Headers)+1)
+	for k, v := range t.Headers {
+		headers[k] = v
+	}
+	if len(t.Body) > 0 && t.ContentType != "" {
+		headers["Content-Type"] = t.ContentType
 	}
+	return headers
 }
@@ -0,0 +1,96 @@
+// Package config holds the configuration of a scan that every module
+// reads, together with the state the command sets up for it: the login
+// session, the scope and the pre-flight results.
+package config
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/preflight"
+	"github.com/aether-0/httpsuite/pkg/scope"
+	"github.com/aether-0/httpsuite/pkg/session"
+)
+
+// Config holds global configuration shared across modules
+type Config struct {
+	URL         string
+	URLs        []string
+	Targets     []common.Target
+	Method      string
+	PayloadDir  string
+	Concurrency int
+	Timeout     time.Duration
+	Retries     int
+	Proxy       *url.URL
+	ProxyStr    string
+	Headers     map[string]string
+	Body        []byte
+	ContentType string
+	UserAgent   string
+	RandomAgent bool
+	Silent      bool
+	Verbose     bool
+	NoColor     bool
+	NoProgress  bool
+	OutputFile  string
+	JSONOutput  bool
+	Formats     []string
+	MinSeverity string
+	Unique      bool
+	Redirect    bool
+	Session     *session.Session
+	Scope       *scope.Scope
+
+	// PreflightEnabled runs the pre-flight phase; Preflight holds its results
+	PreflightEnabled bool
+	Preflight        *preflight.Results
+}
+
+// Default returns a config with sane defaults
+func Default() *Config {
+	return &Config{
+		Method:      "GET",
+		PayloadDir:  "payloads",
+		Concurrency: 10,
+		Timeout:     10 * time.Second,
+		Retries:     1,
+		UserAgent:   "httpsuite/1.0",
+		Headers:     make(map[string]string),
+	}
+}
+
+// ScanTargets returns the targets to scan with their base request resolved.
+// Imported targets keep their own method, headers and body; plain URLs use
+// the configured ones.
+func (c *Config) ScanTargets() []common.Target {
+	if len(c.Targets) == 0 {
+		targets := make([]common.Target, 0, len(c.URLs))
+		for _, u := range c.URLs {
+			targets = append(targets, c.ResolveTarget(common.Target{URL: u}))
+		}
+		return targets
+	}
+
+	targets := make([]common.Target, 0, len(c.Targets))
+	for _, t := range c.Targets {
+		targets = append(targets, c.ResolveTarget(t))
+	}
+	return targets
+}
+
+// ResolveTarget fills the fields a target leaves empty from the base request.
+func (c *Config) ResolveTarget(t common.Target) common.Target {
+	if t.Method == "" {
+		t.Method = c.Method
+		if t.Body == nil {
+			t.Body = c.Body
+			t.ContentType = c.ContentType
+		}
+	}
+	if t.Method == "" {
+		t.Method = "GET"
+	}
+	return t
+}
@@ -1,22 +1,46 @@
 package httpclient
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/aether-0/httpsuite/pkg/scope"
+	"github.com/aether-0/httpsuite/pkg/session"
 )
 
 // Client wraps the standard http.Client with convenience methods
 type Client struct {
-	client    *http.Client
-	userAgent string
-	headers   map[string]string
-	retries   int
-	redirect  bool
+	client      *http.Client
+	userAgent   string
+	headers     map[string]string
+	contentType string
+	retries     int
+	redirect    bool
+	session     *session.Session
+	scope       *scope.Scope
+	timeout     time.Duration
+	dial        scope.DialFunc
+	mask        *DynamicMask
+
+	// http2 is the HTTP/2-only client behind HTTP2, built on first use
+	http2 *http2Client
+}
+
+// http2Client builds the HTTP/2-only client once, so its connections are
+// reused instead of leaking a transport per probe
+type http2Client struct {
+	once   sync.Once
+	client *http.Client
 }
 
 // Options for creating a new Client
@@ -28,25 +52,37 @@ type Options struct {
 	Retries   int
 	Redirect  bool
 	Insecure  bool
+	Session   *session.Session
+
+	// Scope is checked before every request, redirect and raw dial
+	Scope *scope.Scope
+
+	// ContentType is sent with requests that carry a body and set no Content-Type
+	ContentType string
 }
 
 // New creates a new HTTP client with the given options
 func New(opts Options) *Client {
+	dialer := &net.Dialer{
+		Timeout:   opts.Timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	dial := opts.Scope.DialContext(dialer.DialContext)
+
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: opts.Insecure,
 		},
-		DialContext: (&net.Dialer{
-			Timeout:   opts.Timeout,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
+		DialContext:         dial,
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     90 * time.Second,
 	}
 
 	if opts.Proxy != nil {
+		// The proxy resolves target hosts, so only URL rules apply
 		transport.Proxy = http.ProxyURL(opts.Proxy)
+		transport.DialContext = dialer.DialContext
 	}
 
 	checkRedirect := func(req *http.Request, via []*http.Request) error {
@@ -56,6 +92,15 @@ func New(opts Options) *Client {
 		if len(via) >= 10 {
 			return fmt.Errorf("too many redirects")
 		}
+		// Stop at the redirect instead of following it out of scope
+		if opts.Scope.Check(req.URL) != nil {
+			return http.ErrUseLastResponse
+		}
+		if opts.Proxy == nil && opts.Scope.NeedsResolve() {
+			if _, err := opts.Scope.Resolve(req.Context(), req.URL.Hostname()); err != nil {
+				return http.ErrUseLastResponse
+			}
+		}
 		return nil
 	}
 
@@ -80,15 +125,26 @@ func New(opts Options) *Client {
 			Transport:     transport,
 			CheckRedirect: checkRedirect,
 		},
-		userAgent: ua,
-		headers:   opts.Headers,
-		retries:   retries,
-		redirect:  opts.Redirect,
+		userAgent:   ua,
+		headers:     opts.Headers,
+		contentType: opts.ContentType,
+		retries:     retries,
+		redirect:    opts.Redirect,
+		session:     opts.Session,
+		scope:       opts.Scope,
+		timeout:     timeout,
+		dial:        dial,
+		http2:       &http2Client{},
 	}
 }
 
-// Do executes an HTTP request with retries
+// Do executes an HTTP request with retries. Out-of-scope requests are not
+// sent and return an error wrapping scope.ErrOutOfScope.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.checkScope(req); err != nil {
+		return nil, err
+	}
+
 	// Set default headers
 	if req.Header.Get("User-Agent") == "" {
 		req.Header.Set("User-Agent", c.userAgent)
@@ -98,9 +154,35 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 			req.Header.Set(k, v)
 		}
 	}
+	if req.Body != nil && req.Header.Get("Content-Type") == "" && c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+
+	generation := c.session.Generation()
+	resp, err := c.doWithRetries(c.session.Apply(req))
+	if err != nil || !c.session.CanRenew() {
+		return resp, err
+	}
 
+	return c.renewIfLoggedOut(req, resp, generation)
+}
+
+// doWithRetries sends req up to c.retries times. Bodies are rewound through
+// GetBody before every retry, so only replayable bodies are retried.
+func (c *Client) doWithRetries(req *http.Request) (*http.Response, error) {
 	var lastErr error
 	for i := 0; i < c.retries; i++ {
+		if i > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				break
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				break
+			}
+			req.Body = body
+		}
+
 		resp, err := c.client.Do(req)
 		if err == nil {
 			return resp, nil
@@ -113,9 +195,184 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return nil, fmt.Errorf("request failed after %d retries: %w", c.retries, lastErr)
 }
 
+// renewIfLoggedOut logs in again and replays req once when resp matches the
+// session's logged-out fingerprint. Any failure returns the original response.
+func (c *Client) renewIfLoggedOut(req *http.Request, resp *http.Response, generation uint64) (*http.Response, error) {
+	var sample []byte
+	if c.session.NeedsBody() {
+		var err error
+		sample, err = io.ReadAll(io.LimitReader(resp.Body, maxFingerprintBytes))
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(sample), resp.Body), resp.Body}
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	if !c.session.LoggedOut(resp.StatusCode, resp.Header, sample) {
+		return resp, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	if err := c.session.Renew(generation); err != nil {
+		return resp, nil
+	}
+
+	replay := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		replay.Body = body
+	}
+
+	retried, err := c.doWithRetries(c.session.Apply(replay))
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	return retried, nil
+}
+
+// checkScope checks the request URL and any host-routing header values
+func (c *Client) checkScope(req *http.Request) error {
+	if c.scope.Empty() {
+		return nil
+	}
+	if err := c.scope.Check(req.URL); err != nil {
+		return err
+	}
+	hosts := req.Header.Values("Host")
+	if req.Host != "" && req.Host != req.URL.Host {
+		hosts = append(hosts, req.Host)
+	}
+	for _, name := range scope.HostHeaders[1:] {
+		hosts = append(hosts, req.Header.Values(name)...)
+	}
+	for _, value := range hosts {
+		if host := hostOf(value); host != "" {
+			if err := c.checkHeaderHost(req, host); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckRequest checks a request that is not sent through Do, such as a raw
+// request written to a connection from Dial: its URL and every host named in
+// its headers must be in scope.
+func (c *Client) CheckRequest(u *url.URL, headers map[string]string) error {
+	req := &http.Request{URL: u, Host: u.Host, Header: make(http.Header, len(headers))}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+	return c.checkScope(req)
+}
+
+// checkHeaderHost checks a host named in a header. Such hosts are never
+// dialed, so names are resolved here when CIDR rules apply.
+func (c *Client) checkHeaderHost(req *http.Request, host string) error {
+	if err := c.scope.CheckHost(host); err != nil {
+		return err
+	}
+	if !c.scope.NeedsResolve() || net.ParseIP(host) != nil {
+		return nil
+	}
+	if _, err := c.scope.Resolve(req.Context(), host); err != nil {
+		if errors.Is(err, scope.ErrOutOfScope) {
+			return err
+		}
+		return fmt.Errorf("%w: cannot verify host header %s: %v", scope.ErrOutOfScope, host, err)
+	}
+	return nil
+}
+
+// hostOf extracts the host from a Host-style header value, or "" when the
+// value does not name a host.
+func hostOf(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "://") {
+		if parsed, err := url.Parse(value); err == nil {
+			return parsed.Hostname()
+		}
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	if value == "" || strings.ContainsAny(value, " */,;") {
+		return ""
+	}
+	return value
+}
+
+// Dial opens a connection to target's host for senders that write raw
+// requests, using TLS for https targets. A nil tlsConfig skips certificate
+// verification. The target is checked against the scope first.
+func (c *Client) Dial(target *url.URL, tlsConfig *tls.Config) (net.Conn, error) {
+	if err := c.scope.Check(target); err != nil {
+		return nil, err
+	}
+
+	addr := target.Host
+	if target.Port() == "" {
+		switch target.Scheme {
+		case "https":
+			addr = net.JoinHostPort(target.Hostname(), "443")
+		case "http":
+			addr = net.JoinHostPort(target.Hostname(), "80")
+		default:
+			return nil, fmt.Errorf("unsupported scheme: %s", target.Scheme)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx, "tcp", addr)
+	if err != nil || target.Scheme != "https" {
+		return conn, err
+	}
+
+	config := &tls.Config{InsecureSkipVerify: true}
+	if tlsConfig != nil {
+		config = tlsConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = target.Hostname()
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// readCloser pairs a replacement body reader with the original closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// NewRequest creates a request whose body can be replayed on retries and re-logins.
+func NewRequest(method, targetURL string, body []byte) (*http.Request, error) {
+	if body == nil {
+		return http.NewRequest(method, targetURL, nil)
+	}
+	return http.NewRequest(method, targetURL, bytes.NewReader(body))
+}
+
 // InspectRequest makes a request and returns a bounded response fingerprint.
 func (c *Client) InspectRequest(method, targetURL string, extraHeaders map[string]string) (ResponseSummary, error) {
-	req, err := http.NewRequest(method, targetURL, nil)
+	return c.InspectRequestBody(method, targetURL, extraHeaders, nil)
+}
+
+// InspectRequestBody is InspectRequest with a request body.
+func (c *Client) InspectRequestBody(method, targetURL string, extraHeaders map[string]string, body []byte) (ResponseSummary, error) {
+	req, err := NewRequest(method, targetURL, body)
 	if err != nil {
 		return ResponseSummary{}, fmt.Errorf("error creating request: %w", err)
 	}
@@ -124,7 +381,23 @@ func (c *Client) InspectRequest(method, targetURL string, extraHeaders map[strin
 		req.Header.Set(k, v)
 	}
 
-	return c.inspect(req)
+	return c.inspect(req, nil)
+}
+
+// InspectPayload is InspectRequestBody for a mutated request. injected lists
+// the payload values the mutation put into the request; echoes of them in
+// the body are left out of the fingerprint.
+func (c *Client) InspectPayload(method, targetURL string, headers map[string]string, body []byte, injected []string) (ResponseSummary, error) {
+	req, err := NewRequest(method, targetURL, body)
+	if err != nil {
+		return ResponseSummary{}, fmt.Errorf("error creating request: %w", err)
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return c.inspect(req, injected)
 }
 
 // SimpleRequest makes a simple HTTP request and returns the status code and response size.
@@ -136,12 +409,55 @@ func (c *Client) SimpleRequest(method, targetURL string, extraHeaders map[string
 	return summary.StatusCode, summary.ContentLength, nil
 }
 
+// ProbeScheme completes a scheme-relative URL ("//host/path") with the first
+// scheme that gets an HTTP response, trying https before http.
+func (c *Client) ProbeScheme(target string) (string, error) {
+	var lastErr error
+	for _, scheme := range []string{"https", "http"} {
+		candidate := scheme + ":" + target
+		if _, _, err := c.SimpleRequest(http.MethodGet, candidate, nil); err != nil {
+			lastErr = err
+			continue
+		}
+		return candidate, nil
+	}
+	return "", lastErr
+}
+
+// HTTP2 returns a copy of the client that only speaks HTTP/2: negotiated
+// with ALPN for https targets and with prior knowledge (h2c) for http ones.
+// Requests to origins without HTTP/2 support fail instead of falling back.
+// Every copy shares one HTTP/2 transport.
+func (c *Client) HTTP2() *Client {
+	clone := *c
+	if c.http2 == nil {
+		clone.client = c.newHTTP2Client()
+	} else {
+		c.http2.once.Do(func() { c.http2.client = c.newHTTP2Client() })
+		clone.client = c.http2.client
+	}
+	return &clone
+}
+
+func (c *Client) newHTTP2Client() *http.Client {
+	transport := c.GetTransport().Clone()
+	transport.Protocols = new(http.Protocols)
+	transport.Protocols.SetHTTP2(true)
+	transport.Protocols.SetUnencryptedHTTP2(true)
+
+	return &http.Client{
+		Timeout:       c.client.Timeout,
+		Transport:     transport,
+		CheckRedirect: c.client.CheckRedirect,
+	}
+}
+
 // GetTransport returns the underlying transport for advanced use
 func (c *Client) GetTransport() *http.Transport {
 	return c.client.Transport.(*http.Transport)
 }
 
-func (c *Client) inspect(req *http.Request) (ResponseSummary, error) {
+func (c *Client) inspect(req *http.Request, injected []string) (ResponseSummary, error) {
 	resp, err := c.Do(req)
 	if err != nil {
 		return ResponseSummary{}, err
@@ -151,7 +467,7 @@ func (c *Client) inspect(req *http.Request) (ResponseSummary, error) {
 	capture := &limitedCapture{limit: maxFingerprintBytes}
 	buf := make([]byte, 32*1024)
 	contentLength, err := io.CopyBuffer(io.MultiWriter(io.Discard, capture), resp.Body, buf)
-	summary := buildResponseSummary(resp, capture.Bytes(), int(contentLength))
+	summary := c.mask.Apply(buildResponseSummary(resp, capture.Bytes(), int(contentLength), injected))
 	if err != nil {
 		return summary, fmt.Errorf("error reading response body: %w", err)
 	}
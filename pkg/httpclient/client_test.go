@@ -1,10 +1,17 @@
 package httpclient
 
 import (
+	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/scope"
+	"github.com/aether-0/httpsuite/pkg/session"
 )
 
 type roundTripFunc func(*http.Request) (*http.Response, error)
@@ -80,3 +87,140 @@ func newTestClient(body string, headers http.Header, statusCode int) *Client {
 		retries:   1,
 	}
 }
+
+func TestDoRenewsSessionAndReplaysLoggedOutRequest(t *testing.T) {
+	var loggedIn atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			loggedIn.Store(true)
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "fresh", Path: "/"})
+			return
+		}
+		if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "fresh" {
+			_, _ = w.Write([]byte("<form>Please sign in</form>"))
+			return
+		}
+		_, _ = w.Write([]byte("secret area"))
+	}))
+	defer server.Close()
+
+	loggedOut, _ := session.ParseMatcher("Please sign in")
+	sess, err := session.New(session.Options{
+		Cookie:    "sid=stale",
+		Login:     &session.Request{URL: server.URL + "/login"},
+		LoggedOut: loggedOut,
+	})
+	if err != nil {
+		t.Fatalf("session.New returned error: %v", err)
+	}
+
+	client := New(Options{Session: sess})
+	summary, err := client.InspectRequest(http.MethodGet, server.URL+"/admin", nil)
+	if err != nil {
+		t.Fatalf("InspectRequest returned error: %v", err)
+	}
+
+	if !loggedIn.Load() {
+		t.Fatalf("expected a re-login after the logged-out response")
+	}
+	if summary.ContentLength != len("secret area") {
+		t.Fatalf("expected the replayed response, got length %d", summary.ContentLength)
+	}
+}
+
+func TestDoReplaysBodyOnRetry(t *testing.T) {
+	var bodies []string
+	attempts := 0
+	client := &Client{
+		client: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				data, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(data))
+				attempts++
+				if attempts == 1 {
+					return nil, io.ErrUnexpectedEOF
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("ok")),
+					Request:    req,
+				}, nil
+			}),
+		},
+		userAgent:   "httpsuite/1.0",
+		contentType: "application/json",
+		retries:     2,
+	}
+
+	req, err := NewRequest(http.MethodPost, "https://example.com/api", []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[0] != `{"a":1}` || bodies[1] != `{"a":1}` {
+		t.Fatalf("expected the body on both attempts, got %q", bodies)
+	}
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("expected default content type, got %q", req.Header.Get("Content-Type"))
+	}
+}
+
+func TestDoEnforcesScope(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "https://out-of-scope.test/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rules, err := scope.New([]string{"127.0.0.1"}, []string{"path:^/private"})
+	if err != nil {
+		t.Fatalf("scope.New returned error: %v", err)
+	}
+	client := New(Options{Scope: rules, Redirect: true})
+
+	status, _, err := client.SimpleRequest(http.MethodGet, server.URL+"/redirect", nil)
+	if err != nil || status != http.StatusFound {
+		t.Fatalf("expected the out-of-scope redirect to be returned unfollowed, got %d %v", status, err)
+	}
+
+	if _, _, err := client.SimpleRequest(http.MethodGet, server.URL+"/private/x", nil); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected an excluded path to be skipped, got %v", err)
+	}
+	if _, _, err := client.SimpleRequest(http.MethodGet, server.URL+"/", map[string]string{"X-Forwarded-Host": "internal.corp:8080"}); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected an out-of-scope host header to be skipped, got %v", err)
+	}
+	if _, _, err := client.SimpleRequest(http.MethodGet, server.URL+"/", map[string]string{"X-Forwarded-Host": "127.0.0.1"}); err != nil {
+		t.Fatalf("expected an in-scope host header to be sent, got %v", err)
+	}
+
+	parsed, _ := url.Parse("http://192.0.2.1:1/")
+	if _, err := client.Dial(parsed, nil); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected a raw dial to an out-of-scope host to be refused, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected 2 requests to reach the server, got %d", got)
+	}
+}
+
+func TestHTTP2SharesOneTransport(t *testing.T) {
+	client := New(Options{})
+	first, second := client.HTTP2(), client.HTTP2()
+	if first.client != second.client || first.client == client.client {
+		t.Fatal("expected every HTTP/2 copy to share one HTTP/2-only client")
+	}
+	if first.HTTP2().client != first.client {
+		t.Fatal("expected an HTTP/2 copy to reuse its own client")
+	}
+}
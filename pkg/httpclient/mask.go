@@ -0,0 +1,163 @@
+package httpclient
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Patterns for values that change on every request. They run on the
+// lowercased text signature, before it is hashed.
+var dynamicPatterns = []struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "{uuid}"},
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}(?:[t ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:z|[+-]\d{2}:?\d{2})?)?\b`), "{date}"},
+	{regexp.MustCompile(`\b(?:mon|tue|wed|thu|fri|sat|sun), \d{1,2} [a-z]{3} \d{4} \d{2}:\d{2}:\d{2}(?: gmt)?`), "{date}"},
+	{regexp.MustCompile(`\b\d{1,2}/\d{1,2}/\d{2,4}\b`), "{date}"},
+	{regexp.MustCompile(`\b\d{1,2}:\d{2}:\d{2}\b`), "{time}"},
+	{regexp.MustCompile(`\b1\d{9}(?:\d{3})?\b`), "{timestamp}"},
+	{regexp.MustCompile(`\b[0-9a-f]{16,}\b`), "{hex}"},
+}
+
+// maskDynamicValues replaces UUIDs, dates, timestamps and hex IDs with
+// placeholders so they do not change the fingerprint.
+func maskDynamicValues(text string) string {
+	for _, dynamic := range dynamicPatterns {
+		text = dynamic.pattern.ReplaceAllString(text, dynamic.placeholder)
+	}
+	return text
+}
+
+// DynamicMask hides the words of a page that change between identical
+// requests, such as CSRF tokens and request IDs. A masked word is identified
+// by its position and its shape, so a new token value is masked while other
+// content at that position is kept.
+type DynamicMask struct {
+	words []dynamicWord
+}
+
+// Shapes of a changing word, from the strictest
+const (
+	shapeDigits = iota
+	shapeHex
+	shapeToken
+)
+
+// minTokenLen is the length from which letters-only words count as tokens;
+// shorter ones must contain a digit
+const minTokenLen = 12
+
+var (
+	digitsPattern = regexp.MustCompile(`^[0-9]+$`)
+	hexPattern    = regexp.MustCompile(`^[0-9a-f]{8,}$`)
+	tokenPattern  = regexp.MustCompile(`^[a-z0-9_+/=.-]+$`)
+)
+
+// dynamicWord is a word position whose value changed between two responses
+type dynamicWord struct {
+	index    int
+	shape    int
+	min, max int
+}
+
+// shapeOf returns the shape of a token-like word, or -1 for ordinary words
+func shapeOf(word string) int {
+	switch {
+	case digitsPattern.MatchString(word):
+		return shapeDigits
+	case hexPattern.MatchString(word):
+		return shapeHex
+	case tokenPattern.MatchString(word) && (len(word) >= minTokenLen || strings.ContainsAny(word, "0123456789")):
+		return shapeToken
+	}
+	return -1
+}
+
+// matches reports whether word has the learned shape and length
+func (w dynamicWord) matches(word string) bool {
+	shape := shapeOf(word)
+	return shape >= 0 && shape <= w.shape && len(word) >= w.min && len(word) <= w.max
+}
+
+// LearnDynamicMask compares two responses to the same request and returns a
+// mask for the token-like words that differ. It returns nil when nothing
+// token-like changed.
+func LearnDynamicMask(first, second ResponseSummary) *DynamicMask {
+	if first.TextSignature == second.TextSignature {
+		return nil
+	}
+	left := strings.Fields(first.TextSignature)
+	right := strings.Fields(second.TextSignature)
+
+	var changed []int
+	if len(left) == len(right) {
+		for i := range left {
+			if left[i] != right[i] {
+				changed = append(changed, i)
+			}
+		}
+	} else {
+		// Different word counts: only the first word that differs lines up
+		i := 0
+		for i < len(left) && i < len(right) && left[i] == right[i] {
+			i++
+		}
+		if i < len(left) && i < len(right) {
+			changed = append(changed, i)
+		}
+	}
+
+	mask := &DynamicMask{}
+	for _, i := range changed {
+		a, b := shapeOf(left[i]), shapeOf(right[i])
+		if a < 0 || b < 0 {
+			continue
+		}
+		mask.words = append(mask.words, dynamicWord{
+			index: i,
+			shape: max(a, b),
+			min:   min(len(left[i]), len(right[i])),
+			max:   max(len(left[i]), len(right[i])),
+		})
+	}
+	if len(mask.words) == 0 {
+		return nil
+	}
+	return mask
+}
+
+// Apply returns summary with the dynamic words masked and its hash
+// recomputed. A nil mask returns summary unchanged.
+func (m *DynamicMask) Apply(summary ResponseSummary) ResponseSummary {
+	if m == nil || summary.TextSignature == "" {
+		return summary
+	}
+	words := strings.Fields(summary.TextSignature)
+	changed := false
+	for _, dynamic := range m.words {
+		if dynamic.index < len(words) && dynamic.matches(words[dynamic.index]) {
+			words[dynamic.index] = "{dynamic}"
+			changed = true
+		}
+	}
+	if !changed {
+		return summary
+	}
+	summary.TextSignature = strings.Join(words, " ")
+	summary.NormalizedHash = hashText(summary.TextSignature)
+	return summary
+}
+
+// SetDynamicMask masks the summaries of every later request. Set it before
+// sending requests concurrently.
+func (c *Client) SetDynamicMask(mask *DynamicMask) {
+	c.mask = mask
+}
+
+// Summarize is SummarizeResponse with the client's dynamic mask applied, for
+// senders that read responses themselves.
+func (c *Client) Summarize(resp *http.Response, sample []byte, contentLength int, injected ...string) ResponseSummary {
+	return c.mask.Apply(SummarizeResponse(resp, sample, contentLength, injected...))
+}
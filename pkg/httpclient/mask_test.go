@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMaskDynamicValues(t *testing.T) {
+	for in, want := range map[string]string{
+		"request 3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b denied": "request {uuid} denied",
+		"generated at 2026-10-18t12:30:45z by edge":           "generated at {date} by edge",
+		"date: sun, 18 oct 2026 12:30:45 gmt":                 "date: {date}",
+		"seen 18/10/2026 at 12:30:45":                         "seen {date} at {time}",
+		"ray id 8c1e9a4d4e6f8b7a1c2d cached":                  "ray id {hex} cached",
+		"time 1792326645123 ms":                               "time {timestamp} ms",
+		"error 403 forbidden deadbeef":                        "error 403 forbidden deadbeef",
+	} {
+		if got := maskDynamicValues(in); got != want {
+			t.Fatalf("maskDynamicValues(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDynamicMaskStabilizesTokens(t *testing.T) {
+	page := func(token string) ResponseSummary {
+		body := "<html><title>Forbidden</title><body>Access denied. Token: " + token + " Contact support.</body></html>"
+		resp := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Content-Type": []string{"text/html"}}}
+		return SummarizeResponse(resp, []byte(body), len(body))
+	}
+
+	first, second, third := page("Xk29fQ"), page("pL81zA"), page("Qm77aB")
+	if first.NormalizedHash == second.NormalizedHash {
+		t.Fatal("test setup failed: expected the token to change the hash")
+	}
+
+	mask := LearnDynamicMask(first, second)
+	if mask.Apply(first).NormalizedHash != mask.Apply(third).NormalizedHash {
+		t.Fatalf("expected a new token value to be masked, got %q", mask.Apply(third).TextSignature)
+	}
+	if !strings.Contains(mask.Apply(third).TextSignature, "token: {dynamic} contact") {
+		t.Fatalf("unexpected masked signature %q", mask.Apply(third).TextSignature)
+	}
+	if LearnDynamicMask(first, first) != nil {
+		t.Fatal("expected no mask for identical responses")
+	}
+	if got := (*DynamicMask)(nil).Apply(first); got != first {
+		t.Fatal("expected a nil mask to leave the summary unchanged")
+	}
+}
+
+func TestDynamicMaskKeepsOtherContentAtTheSamePlace(t *testing.T) {
+	page := func(title, message string) ResponseSummary {
+		body := "<html><title>" + title + "</title><body>" + message + "</body></html>"
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"text/html"}}}
+		return SummarizeResponse(resp, []byte(body), len(body))
+	}
+
+	blocked := func(token string) ResponseSummary {
+		return page("Denied", "Request "+token+" to the admin area was denied by the firewall.")
+	}
+	mask := LearnDynamicMask(blocked("a1b2c3d4"), blocked("z9y8x7w6"))
+	if mask == nil {
+		t.Fatal("expected the changing request ID to be learned")
+	}
+
+	// Only the word after the anchor ("request") differs, and it is no token
+	bypassed := page("Denied", "Request granted to the admin area was denied by the firewall.")
+	if mask.Apply(bypassed).NormalizedHash == mask.Apply(blocked("q5r6s7t8")).NormalizedHash {
+		t.Fatalf("expected a different page to keep its own hash, got %q", mask.Apply(bypassed).TextSignature)
+	}
+	if !strings.Contains(mask.Apply(bypassed).TextSignature, "request granted to") {
+		t.Fatalf("expected ordinary words to stay unmasked, got %q", mask.Apply(bypassed).TextSignature)
+	}
+
+	// Words that change but do not look like tokens are not masked at all
+	if LearnDynamicMask(page("Status", "Today is monday."), page("Status", "Today is friday.")) != nil {
+		t.Fatal("expected no mask for ordinary words")
+	}
+}
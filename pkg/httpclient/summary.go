@@ -6,7 +6,9 @@ import (
 	"encoding/hex"
 	"html"
 	"net/http"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode/utf8"
 )
@@ -14,6 +16,7 @@ import (
 const (
 	maxFingerprintBytes = 64 * 1024
 	maxTextSignatureLen = 512
+	minReflectedLen     = 3
 )
 
 var (
@@ -62,9 +65,9 @@ func (c *limitedCapture) Bytes() []byte {
 	return c.buf.Bytes()
 }
 
-func buildResponseSummary(resp *http.Response, sample []byte, contentLength int) ResponseSummary {
+func buildResponseSummary(resp *http.Response, sample []byte, contentLength int, injected []string) ResponseSummary {
 	contentType := resp.Header.Get("Content-Type")
-	title, text, normalizedHash, isHTML := fingerprintResponseSample(sample, contentType)
+	title, text, normalizedHash, isHTML := fingerprintResponseSample(sample, contentType, reflectedForms(injected))
 
 	return ResponseSummary{
 		StatusCode:     resp.StatusCode,
@@ -78,11 +81,45 @@ func buildResponseSummary(resp *http.Response, sample []byte, contentLength int)
 }
 
 // SummarizeResponse builds a bounded response summary from a sampled body.
-func SummarizeResponse(resp *http.Response, sample []byte, contentLength int) ResponseSummary {
-	return buildResponseSummary(resp, sample, contentLength)
+// Injected payload values (the mutated request-target, injected header
+// values) that the body echoes back are left out of the fingerprint, so a
+// block page that prints the requested path stays the same template for
+// every payload.
+func SummarizeResponse(resp *http.Response, sample []byte, contentLength int, injected ...string) ResponseSummary {
+	return buildResponseSummary(resp, sample, contentLength, injected)
 }
 
-func fingerprintResponseSample(sample []byte, contentType string) (title, text, normalizedHash string, isHTML bool) {
+// reflectedForms lists the values raw and decoded, with their URL- and
+// HTML-encoded forms, longest first.
+func reflectedForms(values []string) []string {
+	seen := make(map[string]struct{})
+	var forms []string
+	add := func(value string) {
+		value = strings.ToLower(value)
+		// Short values would match unrelated words
+		if len(value) < minReflectedLen {
+			return
+		}
+		if _, ok := seen[value]; ok {
+			return
+		}
+		seen[value] = struct{}{}
+		forms = append(forms, value)
+	}
+	for _, value := range values {
+		add(value)
+		if decoded, err := url.PathUnescape(value); err == nil {
+			add(decoded)
+		}
+		add(url.QueryEscape(value))
+		add(html.EscapeString(value))
+	}
+
+	sort.Slice(forms, func(i, j int) bool { return len(forms[i]) > len(forms[j]) })
+	return forms
+}
+
+func fingerprintResponseSample(sample []byte, contentType string, reflected []string) (title, text, normalizedHash string, isHTML bool) {
 	raw := string(sample)
 	lowerRaw := strings.ToLower(raw)
 	lowerContentType := strings.ToLower(contentType)
@@ -94,7 +131,7 @@ func fingerprintResponseSample(sample []byte, contentType string) (title, text,
 
 	if isHTML {
 		if match := titlePattern.FindStringSubmatch(raw); len(match) == 2 {
-			title = collapseWhitespace(html.UnescapeString(match[1]))
+			title = stripReflectedTitle(collapseWhitespace(html.UnescapeString(match[1])), reflected)
 		}
 
 		raw = scriptPattern.ReplaceAllString(raw, " ")
@@ -104,14 +141,36 @@ func fingerprintResponseSample(sample []byte, contentType string) (title, text,
 		raw = html.UnescapeString(raw)
 	}
 
-	text = truncateRunes(collapseWhitespace(strings.ToLower(raw)), maxTextSignatureLen)
+	raw = strings.ToLower(raw)
+	for _, value := range reflected {
+		raw = strings.ReplaceAll(raw, value, " ")
+	}
+
+	text = truncateRunes(maskDynamicValues(collapseWhitespace(raw)), maxTextSignatureLen)
 	if text == "" {
 		sum := sha256.Sum256(sample)
 		return title, "", hex.EncodeToString(sum[:8]), isHTML
 	}
 
+	return title, text, hashText(text), isHTML
+}
+
+// stripReflectedTitle removes the reflected values from title like from the
+// body text, so block pages that echo the path in their title keep one
+// title. Matching ignores case; the rest of the title keeps its case.
+func stripReflectedTitle(title string, reflected []string) string {
+	if title == "" {
+		return title
+	}
+	for _, value := range reflected {
+		title = regexp.MustCompile("(?i)"+regexp.QuoteMeta(value)).ReplaceAllString(title, " ")
+	}
+	return collapseWhitespace(title)
+}
+
+func hashText(text string) string {
 	sum := sha256.Sum256([]byte(text))
-	return title, text, hex.EncodeToString(sum[:8]), isHTML
+	return hex.EncodeToString(sum[:8])
 }
 
 func collapseWhitespace(s string) string {
@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"html"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSummarizeResponseIgnoresReflectedPayload(t *testing.T) {
+	summarize := func(echoed string, injected ...string) ResponseSummary {
+		body := "<html><title>Forbidden</title><body><p>Access to " + echoed + " is denied.</p></body></html>"
+		resp := &http.Response{
+			StatusCode: http.StatusForbidden,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		}
+		return SummarizeResponse(resp, []byte(body), len(body), injected...)
+	}
+
+	base := summarize("/admin", "/admin")
+	for _, tc := range []struct {
+		name, echoed string
+		injected     []string
+	}{
+		{"raw path", "/admin..;/", []string{"/admin..;/"}},
+		{"decoded path", "/admin..;/", []string{"/admin%2e%2e;/"}},
+		{"encoded path", url.QueryEscape("/admin..;/"), []string{"/admin..;/"}},
+		{"html-encoded path", html.EscapeString("/admin/<x>"), []string{"/admin/<x>"}},
+		{"query", "/admin?debug=true", []string{"/admin?debug=true", "/admin"}},
+		{"header value", "/internal/admin", []string{"/admin", "/internal/admin"}},
+	} {
+		if got := summarize(tc.echoed, tc.injected...); got.NormalizedHash != base.NormalizedHash {
+			t.Fatalf("%s: expected the reflected value to be ignored, got %q vs %q", tc.name, got.TextSignature, base.TextSignature)
+		}
+	}
+
+	if other := summarize("the dashboard", "/admin"); other.NormalizedHash == base.NormalizedHash {
+		t.Fatal("expected a different page to keep a different fingerprint")
+	}
+	// Values that were not injected, such as the logged-in user, are kept
+	if user := summarize("/admin as alice", "/admin"); user.NormalizedHash == base.NormalizedHash {
+		t.Fatal("expected echoed values that were not injected to change the fingerprint")
+	}
+}
+
+func TestSummarizeResponseIgnoresPayloadReflectedInTitle(t *testing.T) {
+	summarize := func(path string) ResponseSummary {
+		body := "<html><head><title>403 - " + path + "</title></head><body><p>Access is denied.</p></body></html>"
+		resp := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Content-Type": []string{"text/html"}}}
+		return SummarizeResponse(resp, []byte(body), len(body), path)
+	}
+
+	base, payload := summarize("/admin"), summarize("/Admin%2e")
+	if base.Title != "403 -" || payload.Title != base.Title {
+		t.Fatalf("expected the reflected path to be stripped from the title, got %q and %q", base.Title, payload.Title)
+	}
+	if payload.NormalizedHash != base.NormalizedHash {
+		t.Fatalf("expected the same fingerprint, got %q vs %q", payload.TextSignature, base.TextSignature)
+	}
+}
@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// harSkippedHeaders are recomputed by the client and not replayed from HAR entries
+var harSkippedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Content-Type":      true,
+	"Connection":        true,
+	"Accept-Encoding":   true,
+	"Transfer-Encoding": true,
+}
+
+// parseHAR converts every request of a HAR capture into a target with its
+// method, headers and body.
+func parseHAR(data []byte) ([]common.Target, error) {
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("invalid HAR: %w", err)
+	}
+
+	targets := make([]common.Target, 0, len(har.Log.Entries))
+	for _, entry := range har.Log.Entries {
+		req := entry.Request
+		if !isRemote(req.URL) {
+			continue
+		}
+
+		target := common.Target{
+			URL:     req.URL,
+			Method:  strings.ToUpper(req.Method),
+			Headers: make(map[string]string),
+		}
+		for _, h := range req.Headers {
+			// HTTP/2 captures carry pseudo-headers such as :authority
+			if strings.HasPrefix(h.Name, ":") {
+				continue
+			}
+			name := http.CanonicalHeaderKey(h.Name)
+			if harSkippedHeaders[name] {
+				continue
+			}
+			if existing, ok := target.Headers[name]; ok && name == "Cookie" {
+				target.Headers[name] = existing + "; " + h.Value
+				continue
+			}
+			target.Headers[name] = h.Value
+		}
+		if req.PostData != nil && req.PostData.Text != "" {
+			target.Body = []byte(req.PostData.Text)
+			target.ContentType = req.PostData.MimeType
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
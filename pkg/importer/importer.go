@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// maxSourceBytes bounds how much of an import source is read
+const maxSourceBytes = 64 << 20
+
+// Options controls how import sources are fetched and resolved
+type Options struct {
+	// BaseURL overrides the server URL of OpenAPI specs
+	BaseURL string
+	Timeout time.Duration
+	Proxy   *url.URL
+}
+
+// Load reads a HAR file, OpenAPI/Swagger spec, or sitemap.xml from a local
+// path or an http(s) URL and converts it into scan targets. The format is
+// detected from the content.
+func Load(source string, opts Options) ([]common.Target, error) {
+	data, err := opts.read(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []common.Target
+	switch detectFormat(data) {
+	case "har":
+		targets, err = parseHAR(data)
+	case "openapi":
+		targets, err = parseOpenAPI(data, source, opts.BaseURL)
+	case "sitemap":
+		targets, err = parseSitemap(data, opts, 0)
+	default:
+		return nil, fmt.Errorf("%s: unrecognised import format (expected HAR, OpenAPI/Swagger, or sitemap.xml)", source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	return dedupe(targets), nil
+}
+
+func detectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return "sitemap"
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+		return ""
+	}
+	if _, ok := doc["log"]; ok {
+		return "har"
+	}
+	if _, ok := doc["openapi"]; ok {
+		return "openapi"
+	}
+	if _, ok := doc["swagger"]; ok {
+		return "openapi"
+	}
+	return ""
+}
+
+func (o Options) read(source string) ([]byte, error) {
+	if !isRemote(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("error reading import file: %w", err)
+		}
+		return data, nil
+	}
+
+	timeout := o.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	transport := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	if o.Proxy != nil {
+		transport.Proxy = http.ProxyURL(o.Proxy)
+	}
+	client := &http.Client{Timeout: timeout, Transport: transport}
+
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: status %d", source, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSourceBytes))
+}
+
+func isRemote(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// dedupe drops targets that repeat the same method, URL, and body
+func dedupe(targets []common.Target) []common.Target {
+	seen := make(map[string]bool, len(targets))
+	unique := targets[:0]
+	for _, t := range targets {
+		key := t.Method + " " + t.URL + "\x00" + string(t.Body)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, t)
+	}
+	return unique
+}
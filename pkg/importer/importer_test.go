@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSource(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadHAR(t *testing.T) {
+	path := writeSource(t, "capture.har", `{"log":{"entries":[
+{"request":{"method":"post","url":"https://app.example.com/api/users",
+ "headers":[{"name":":authority","value":"app.example.com"},{"name":"cookie","value":"a=1"},{"name":"Cookie","value":"b=2"},
+            {"name":"Content-Length","value":"12"},{"name":"X-Csrf","value":"tok"}],
+ "postData":{"mimeType":"application/json","text":"{\"name\":\"x\"}"}}},
+{"request":{"method":"GET","url":"https://app.example.com/","headers":[]}},
+{"request":{"method":"GET","url":"https://app.example.com/","headers":[]}}
+]}}`)
+
+	targets, err := Load(path, Options{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected duplicate entries to be dropped, got %d targets", len(targets))
+	}
+
+	post := targets[0]
+	if post.Method != "POST" || post.URL != "https://app.example.com/api/users" {
+		t.Fatalf("unexpected request: %s %s", post.Method, post.URL)
+	}
+	if string(post.Body) != `{"name":"x"}` || post.ContentType != "application/json" {
+		t.Fatalf("unexpected body %q (%s)", post.Body, post.ContentType)
+	}
+	if post.Headers["Cookie"] != "a=1; b=2" || post.Headers["X-Csrf"] != "tok" {
+		t.Fatalf("unexpected headers: %v", post.Headers)
+	}
+	for _, dropped := range []string{":authority", "Content-Length"} {
+		if _, ok := post.Headers[dropped]; ok {
+			t.Fatalf("expected %s to be dropped", dropped)
+		}
+	}
+}
+
+func TestLoadOpenAPI3(t *testing.T) {
+	path := writeSource(t, "openapi.yaml", `openapi: 3.0.0
+servers:
+  - url: https://{env}.example.com/v1
+    variables:
+      env:
+        default: api
+paths:
+  /users/{id}:
+    parameters:
+      - $ref: '#/components/parameters/UserID'
+    get:
+      parameters:
+        - name: fields
+          in: query
+          required: true
+          schema: {type: string, enum: [name, email]}
+        - name: page
+          in: query
+          schema: {type: integer}
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+components:
+  parameters:
+    UserID:
+      name: id
+      in: path
+      required: true
+      example: 42
+  schemas:
+    User:
+      type: object
+      properties:
+        active: {type: boolean}
+`)
+
+	targets, err := Load(path, Options{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(targets))
+	}
+
+	get, put := targets[0], targets[1]
+	if get.Method != "GET" || get.URL != "https://api.example.com/v1/users/42?fields=name" {
+		t.Fatalf("unexpected GET target: %s %s", get.Method, get.URL)
+	}
+	if put.Method != "PUT" || string(put.Body) != `{"active":true}` || put.ContentType != "application/json" {
+		t.Fatalf("unexpected PUT target: %s body=%q type=%s", put.Method, put.Body, put.ContentType)
+	}
+
+	targets, err = Load(path, Options{BaseURL: "http://localhost:8080"})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if targets[0].URL != "http://localhost:8080/users/42?fields=name" {
+		t.Fatalf("expected the base override to win, got %s", targets[0].URL)
+	}
+}
+
+func TestLoadSwagger2(t *testing.T) {
+	path := writeSource(t, "swagger.json", `{"swagger":"2.0","host":"legacy.example.com","basePath":"/api","schemes":["http"],
+"paths":{"/orders":{"post":{"parameters":[{"name":"body","in":"body","schema":{"type":"object","properties":{"qty":{"type":"integer"}}}},
+{"name":"X-Tenant","in":"header","required":true,"type":"string"}]}}}}`)
+
+	targets, err := Load(path, Options{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(targets))
+	}
+	target := targets[0]
+	if target.URL != "http://legacy.example.com/api/orders" || target.Method != "POST" {
+		t.Fatalf("unexpected target: %s %s", target.Method, target.URL)
+	}
+	if string(target.Body) != `{"qty":1}` || target.Headers["X-Tenant"] != "test" {
+		t.Fatalf("unexpected body %q or headers %v", target.Body, target.Headers)
+	}
+}
+
+func TestLoadOpenAPIWithoutServerNeedsBase(t *testing.T) {
+	path := writeSource(t, "openapi.json", `{"openapi":"3.1.0","paths":{"/health":{"get":{}}}}`)
+	if _, err := Load(path, Options{}); err == nil {
+		t.Fatalf("expected an error for a spec without an absolute server URL")
+	}
+}
+
+func TestLoadRemoteSitemapIndex(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/pages.xml</loc></sitemap></sitemapindex>`, server.URL)
+		case "/pages.xml":
+			fmt.Fprint(w, `<urlset><url><loc>https://example.com/about</loc></url><url><loc>relative</loc></url></urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	targets, err := Load(server.URL+"/sitemap.xml", Options{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(targets) != 1 || targets[0].URL != "https://example.com/about" || targets[0].Method != "GET" {
+		t.Fatalf("unexpected targets: %+v", targets)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	cases := map[string]string{
+		`{"log":{"entries":[]}}`:          "har",
+		"openapi: 3.0.0\npaths: {}":       "openapi",
+		`{"swagger":"2.0"}`:               "openapi",
+		"  <urlset></urlset>":             "sitemap",
+		"https://example.com/just-a-list": "",
+	}
+	for input, want := range cases {
+		if got := detectFormat([]byte(input)); got != want {
+			t.Errorf("detectFormat(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// maxSchemaDepth stops example synthesis on deeply nested or recursive schemas
+const maxSchemaDepth = 6
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// spec wraps a decoded OpenAPI 3 or Swagger 2 document
+type spec struct {
+	root map[string]any
+}
+
+// parseOpenAPI expands every path and operation into a target. Path
+// templates, required query parameters and JSON bodies are filled with
+// example values from the spec, or type-based defaults when none exist.
+func parseOpenAPI(data []byte, source, baseOverride string) ([]common.Target, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	s := &spec{root: root}
+
+	base, err := s.baseURL(source, baseOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := asMap(root["paths"])
+	pathNames := make([]string, 0, len(paths))
+	for name := range paths {
+		pathNames = append(pathNames, name)
+	}
+	sort.Strings(pathNames)
+
+	var targets []common.Target
+	for _, pathName := range pathNames {
+		item := s.resolve(paths[pathName])
+		shared := asSlice(item["parameters"])
+
+		for _, method := range openAPIMethods {
+			op, ok := item[method]
+			if !ok {
+				continue
+			}
+			operation := s.resolve(op)
+			params := append(append([]any{}, shared...), asSlice(operation["parameters"])...)
+
+			target, err := s.buildTarget(base, pathName, strings.ToUpper(method), params, operation)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
+
+// baseURL picks the server URL: the override, then servers[0] (OpenAPI 3),
+// then schemes/host/basePath (Swagger 2). Relative servers resolve against
+// the spec's own URL when it was fetched remotely.
+func (s *spec) baseURL(source, override string) (string, error) {
+	var base string
+	switch {
+	case override != "":
+		base = override
+	case len(asSlice(s.root["servers"])) > 0:
+		base = asString(asMap(asSlice(s.root["servers"])[0])["url"])
+		for name, variable := range asMap(asMap(asSlice(s.root["servers"])[0])["variables"]) {
+			base = strings.ReplaceAll(base, "{"+name+"}", asString(asMap(variable)["default"]))
+		}
+	case asString(s.root["host"]) != "":
+		scheme := "https"
+		if schemes := asSlice(s.root["schemes"]); len(schemes) > 0 && !containsValue(schemes, "https") {
+			scheme = asString(schemes[0])
+		}
+		base = scheme + "://" + asString(s.root["host"]) + asString(s.root["basePath"])
+	}
+
+	if isRemote(base) {
+		return strings.TrimRight(base, "/"), nil
+	}
+	if isRemote(source) {
+		ref, err := url.Parse(base)
+		if err != nil {
+			return "", fmt.Errorf("invalid server URL %q: %w", base, err)
+		}
+		resolved, _ := url.Parse(source)
+		return strings.TrimRight(resolved.ResolveReference(ref).String(), "/"), nil
+	}
+	return "", fmt.Errorf("spec has no absolute server URL; pass --import-base")
+}
+
+func (s *spec) buildTarget(base, pathName, method string, params []any, operation map[string]any) (common.Target, error) {
+	target := common.Target{
+		Method:  method,
+		Headers: make(map[string]string),
+	}
+
+	path := pathName
+	query := url.Values{}
+	for _, p := range params {
+		param := s.resolve(p)
+		name := asString(param["name"])
+		value := fmt.Sprint(s.paramExample(param))
+
+		switch asString(param["in"]) {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+		case "query":
+			if required, _ := param["required"].(bool); required {
+				query.Set(name, value)
+			}
+		case "header":
+			if required, _ := param["required"].(bool); required {
+				target.Headers[name] = value
+			}
+		case "body":
+			// Swagger 2 body parameter
+			body, err := json.Marshal(s.example(param["schema"], 0))
+			if err != nil {
+				return target, err
+			}
+			target.Body = body
+			target.ContentType = "application/json"
+		}
+	}
+
+	if requestBody := s.resolve(operation["requestBody"]); requestBody != nil {
+		if body, contentType, ok := s.requestBodyExample(requestBody); ok {
+			target.Body = body
+			target.ContentType = contentType
+		}
+	}
+
+	target.URL = base + path
+	if encoded := query.Encode(); encoded != "" {
+		target.URL += "?" + encoded
+	}
+	return target, nil
+}
+
+// requestBodyExample prefers a JSON body, then form encoding
+func (s *spec) requestBodyExample(requestBody map[string]any) ([]byte, string, bool) {
+	content := asMap(requestBody["content"])
+
+	for contentType, media := range content {
+		if !strings.Contains(contentType, "json") {
+			continue
+		}
+		m := asMap(media)
+		value := m["example"]
+		if value == nil {
+			value = s.example(m["schema"], 0)
+		}
+		body, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		return body, contentType, true
+	}
+
+	if media, ok := content["application/x-www-form-urlencoded"]; ok {
+		form := url.Values{}
+		if obj, ok := s.example(asMap(media)["schema"], 0).(map[string]any); ok {
+			for k, v := range obj {
+				form.Set(k, fmt.Sprint(v))
+			}
+		}
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", true
+	}
+
+	return nil, "", false
+}
+
+func (s *spec) paramExample(param map[string]any) any {
+	if v, ok := param["example"]; ok {
+		return v
+	}
+	for _, example := range asMap(param["examples"]) {
+		if v, ok := s.resolve(example)["value"]; ok {
+			return v
+		}
+	}
+	if schema, ok := param["schema"]; ok {
+		return s.example(schema, 0)
+	}
+	// Swagger 2 keeps the type on the parameter itself
+	return s.example(param, 0)
+}
+
+// example synthesises a value for a schema
+func (s *spec) example(node any, depth int) any {
+	schema := s.resolve(node)
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	for _, key := range []string{"example", "default"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if enum := asSlice(schema["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if options := asSlice(schema[key]); len(options) > 0 {
+			if key != "allOf" {
+				return s.example(options[0], depth+1)
+			}
+			merged := make(map[string]any)
+			for _, option := range options {
+				if obj, ok := s.example(option, depth+1).(map[string]any); ok {
+					for k, v := range obj {
+						merged[k] = v
+					}
+				}
+			}
+			return merged
+		}
+	}
+
+	switch asString(schema["type"]) {
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []any{s.example(schema["items"], depth+1)}
+	case "object", "":
+		properties := asMap(schema["properties"])
+		if len(properties) == 0 && asString(schema["type"]) == "" {
+			return "test"
+		}
+		obj := make(map[string]any, len(properties))
+		for name, prop := range properties {
+			obj[name] = s.example(prop, depth+1)
+		}
+		return obj
+	default:
+		return stringExample(asString(schema["format"]))
+	}
+}
+
+func stringExample(format string) string {
+	switch format {
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000001"
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "test@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	default:
+		return "test"
+	}
+}
+
+// resolve follows local $ref pointers such as #/components/schemas/User
+func (s *spec) resolve(node any) map[string]any {
+	obj := asMap(node)
+	for i := 0; i < 10 && obj != nil; i++ {
+		ref := asString(obj["$ref"])
+		if !strings.HasPrefix(ref, "#/") {
+			return obj
+		}
+		var current any = s.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			current = asMap(current)[part]
+		}
+		obj = asMap(current)
+	}
+	return obj
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func containsValue(items []any, want string) bool {
+	for _, item := range items {
+		if asString(item) == want {
+			return true
+		}
+	}
+	return false
+}
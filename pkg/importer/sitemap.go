@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// maxSitemapDepth limits how many levels of sitemap indexes are followed
+const maxSitemapDepth = 2
+
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// parseSitemap reads a <urlset> as GET targets. Entries of a <sitemapindex>
+// are fetched and expanded when they are reachable http(s) URLs.
+func parseSitemap(data []byte, opts Options, depth int) ([]common.Target, error) {
+	var doc sitemapDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid sitemap: %w", err)
+	}
+
+	var targets []common.Target
+	for _, entry := range doc.URLs {
+		loc := strings.TrimSpace(entry.Loc)
+		if isRemote(loc) {
+			targets = append(targets, common.Target{URL: loc, Method: http.MethodGet})
+		}
+	}
+
+	for _, entry := range doc.Sitemaps {
+		loc := strings.TrimSpace(entry.Loc)
+		if !isRemote(loc) || depth >= maxSitemapDepth {
+			continue
+		}
+		nested, err := opts.read(loc)
+		if err != nil {
+			return nil, err
+		}
+		children, err := parseSitemap(nested, opts, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", loc, err)
+		}
+		targets = append(targets, children...)
+	}
+
+	return targets, nil
+}
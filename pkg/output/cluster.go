@@ -0,0 +1,125 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// cluster collects results that got the same response for one target. Only
+// the first is written as it arrives; the rest are summarized on Close.
+type cluster struct {
+	result   common.ScanResult
+	index    int
+	count    int
+	payloads []string
+}
+
+// SetUnique clusters results by target, status code and fingerprint
+func (p *Printer) SetUnique(unique bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.unique = unique
+}
+
+func clusterKey(r common.ScanResult) string {
+	return r.Target + "\x00" + strconv.Itoa(r.StatusCode) + "\x00" + r.Fingerprint
+}
+
+// payloadOf names what triggered r, for the cluster's payload list
+func payloadOf(r common.ScanResult) string {
+	switch {
+	case r.Payload != "" && r.Technique != "":
+		return r.Technique + ": " + r.Payload
+	case r.Payload != "":
+		return r.Payload
+	case r.Detail != "":
+		return r.Detail
+	default:
+		return r.URL
+	}
+}
+
+// joinClusterLocked adds r to an existing cluster and reports whether it
+// did. Results without a fingerprint are never clustered. p.mu must be held.
+func (p *Printer) joinClusterLocked(r common.ScanResult) bool {
+	if r.Fingerprint == "" {
+		return false
+	}
+	c, ok := p.clusters[clusterKey(r)]
+	if !ok {
+		return false
+	}
+	c.count++
+	c.payloads = append(c.payloads, payloadOf(r))
+	if c.index >= 0 {
+		p.results[c.index].Count = c.count
+		p.results[c.index].Payloads = append([]string(nil), c.payloads...)
+	}
+	return true
+}
+
+// startClusterLocked makes a written result the representative of a new
+// cluster. p.mu must be held.
+func (p *Printer) startClusterLocked(r common.ScanResult) {
+	if r.Fingerprint == "" {
+		return
+	}
+	if p.clusters == nil {
+		p.clusters = make(map[string]*cluster)
+	}
+	c := &cluster{result: r, index: -1, count: 1, payloads: []string{payloadOf(r)}}
+	if len(p.reports) > 0 {
+		c.index = len(p.results) - 1
+	}
+	p.clusters[clusterKey(r)] = c
+	p.clusterOrder = append(p.clusterOrder, c)
+}
+
+// writeClustersLocked writes a summary record for every cluster that
+// absorbed duplicates, with its count and payloads. The summary has its own
+// id and points to the representative through cluster_of, so every id is
+// still written once. p.mu must be held.
+func (p *Printer) writeClustersLocked() {
+	var merged []*cluster
+	for _, c := range p.clusterOrder {
+		if c.count > 1 {
+			merged = append(merged, c)
+		}
+	}
+	p.clusters, p.clusterOrder = nil, nil
+	if len(merged) == 0 {
+		return
+	}
+
+	if !p.jsonMode && !p.silent {
+		fmt.Printf("\n%s━━━━━━━━━━━━━━ %s ━━━━━━━━━━━━━━%s\n", p.magenta(), "CLUSTERED RESULTS", p.reset())
+	}
+	for _, c := range merged {
+		r := c.result
+		r.Record = common.RecordCluster
+		r.ClusterOf = r.ID
+		r.ID = clusterID(r.ID)
+		r.Count = c.count
+		r.Payloads = c.payloads
+		p.emitLocked(r)
+	}
+}
+
+// clusterID derives the id of a cluster summary from its representative's
+func clusterID(id string) string {
+	sum := sha256.Sum256([]byte(common.RecordCluster + "\x00" + id))
+	return hex.EncodeToString(sum[:8])
+}
+
+// clusterSuffix lists how many results a representative stands for
+func clusterSuffix(r common.ScanResult) string {
+	if r.Count < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" x%d: %s", r.Count, strings.Join(r.Payloads, ", "))
+}
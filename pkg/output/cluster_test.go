@@ -0,0 +1,76 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+func TestUniqueClustersSameResponse(t *testing.T) {
+	dir := t.TempDir()
+	printer := NewPrinter(true, true, true, filepath.Join(dir, "scan.jsonl"))
+	printer.SetUnique(true)
+	if err := printer.AddOutput(FormatMarkdown, filepath.Join(dir, "scan.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "localhost"} {
+		printer.Result(common.ScanResult{URL: "https://example.com/admin", Target: "https://example.com/admin", StatusCode: 200,
+			Fingerprint: "abc", Module: "bypass", Technique: "header bypass", Payload: "X-Forwarded-For: " + ip,
+			Detail: "header bypass -> X-Forwarded-For: " + ip, Reason: "status 403 -> 200", Severity: common.SeverityHigh})
+	}
+	// A different response and a result without a fingerprint stay separate
+	printer.Result(common.ScanResult{URL: "https://example.com/admin/", Target: "https://example.com/admin", StatusCode: 200,
+		Fingerprint: "def", Module: "bypass", Technique: "endpath", Payload: "/", Severity: common.SeverityHigh})
+	printer.Result(common.ScanResult{URL: "https://example.com/", Target: "https://example.com/", StatusCode: 200, Module: "cors", Vulnerable: true})
+	printer.Result(common.ScanResult{URL: "https://example.com/", Target: "https://example.com/", StatusCode: 200, Module: "cors", Vulnerable: true})
+	printer.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "scan.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	// Two bypass representatives and both cors results, then the cluster summary
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 records, got %d:\n%s", len(lines), data)
+	}
+
+	var first, summary common.ScanResult
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[4]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Record != common.RecordCluster || summary.ClusterOf != first.ID || summary.Count != 3 || len(summary.Payloads) != 3 {
+		t.Fatalf("unexpected cluster summary: %+v", summary)
+	}
+	// Every id is written once
+	ids := make(map[string]bool)
+	for _, line := range lines {
+		var r common.ScanResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Module == "bypass" && ids[r.ID] {
+			t.Fatalf("id %s written twice:\n%s", r.ID, data)
+		}
+		ids[r.ID] = true
+	}
+	if summary.Payloads[2] != "header bypass: X-Forwarded-For: localhost" {
+		t.Fatalf("unexpected payloads: %v", summary.Payloads)
+	}
+
+	report, err := os.ReadFile(filepath.Join(dir, "scan.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "(3 results: header bypass: X-Forwarded-For: 127.0.0.1") {
+		t.Fatalf("expected the cluster in the report:\n%s", report)
+	}
+}
@@ -0,0 +1,196 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/report"
+)
+
+// Output formats accepted by AddOutput
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatJSONL    = "jsonl"
+	FormatSARIF    = "sarif"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists every output format in the order they are documented
+var Formats = []string{FormatText, FormatJSON, FormatJSONL, FormatSARIF, FormatMarkdown, FormatHTML}
+
+var formatExtensions = map[string]string{
+	FormatText:     ".txt",
+	FormatJSON:     ".json",
+	FormatJSONL:    ".jsonl",
+	FormatSARIF:    ".sarif",
+	FormatMarkdown: ".md",
+	FormatHTML:     ".html",
+}
+
+// IsFormat reports whether name is a supported output format
+func IsFormat(name string) bool {
+	_, ok := formatExtensions[name]
+	return ok
+}
+
+// FormatPath derives the file for one of several formats written from the
+// same -o path by replacing its extension.
+func FormatPath(base, format string) string {
+	return strings.TrimSuffix(base, filepath.Ext(base)) + formatExtensions[format]
+}
+
+// fileSink streams results to a text, JSON array or JSON Lines file as they
+// arrive. JSON Lines files are valid after every record.
+type fileSink struct {
+	file      *os.File
+	format    string
+	hasResult bool
+}
+
+// reportSink is written once, from every collected result, on Close
+type reportSink struct {
+	format string
+	path   string
+}
+
+// AddOutput writes results to path in format. Text, JSON and JSON Lines
+// files are streamed; SARIF, Markdown and HTML reports are written on Close.
+func (p *Printer) AddOutput(format, path string) error {
+	if !IsFormat(format) {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if format != FormatText && format != FormatJSON && format != FormatJSONL {
+		p.reports = append(p.reports, reportSink{format: format, path: path})
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	sink := &fileSink{file: f, format: format}
+	if format == FormatJSON {
+		if _, err := f.WriteString("[\n"); err != nil {
+			f.Close()
+			return fmt.Errorf("error initializing JSON output file: %w", err)
+		}
+	}
+	p.files = append(p.files, sink)
+	return nil
+}
+
+func (f *fileSink) write(r common.ScanResult) {
+	switch f.format {
+	case FormatJSON:
+		data, _ := json.Marshal(r)
+		if f.hasResult {
+			f.file.WriteString(",\n")
+		}
+		f.file.Write(data)
+		f.hasResult = true
+		return
+	case FormatJSONL:
+		data, _ := json.Marshal(r)
+		f.file.Write(append(data, '\n'))
+		return
+	}
+
+	line := fmt.Sprintf("%d %s %s %d bytes", r.StatusCode, r.Method, r.URL, r.ContentLength)
+	if r.Detail != "" {
+		line += " (" + r.Detail + ")"
+	}
+	if r.Vulnerable {
+		line += " [VULNERABLE]"
+	}
+	if r.Severity != common.SeverityInfo {
+		line += " [" + severityLabel(r) + "]"
+	}
+	line += " [" + r.Module + "]"
+	line += clusterSuffix(r)
+	fmt.Fprintln(f.file, line)
+}
+
+func (f *fileSink) close() {
+	if f.format == FormatJSON {
+		if f.hasResult {
+			f.file.WriteString("\n]\n")
+		} else {
+			f.file.WriteString("]\n")
+		}
+	}
+	f.file.Close()
+}
+
+// writeReportsLocked renders every report sink. p.mu must be held.
+func (p *Printer) writeReportsLocked() {
+	meta := report.Meta{
+		Tool:     "httpsuite",
+		Version:  common.Version,
+		Started:  p.started,
+		Finished: time.Now(),
+	}
+	for _, sink := range p.reports {
+		if err := writeReport(sink, p.results, meta); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s report: %v\n", sink.format, err)
+		}
+	}
+	p.reports = nil
+}
+
+func writeReport(sink reportSink, results []common.ScanResult, meta report.Meta) error {
+	f, err := os.Create(sink.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch sink.format {
+	case FormatSARIF:
+		return report.WriteSARIF(f, results, meta)
+	case FormatMarkdown:
+		return report.WriteMarkdown(f, results, meta)
+	default:
+		return report.WriteHTML(f, results, meta)
+	}
+}
+
+// ReadResults loads results written as a JSON array (-o with json) or as one
+// JSON object per line (jsonl files and -j stdout). Blank lines are skipped.
+func ReadResults(path string) ([]common.ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []common.ScanResult
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &results); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return results, nil
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var r common.ScanResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+func TestFormatPath(t *testing.T) {
+	if got := FormatPath("out/scan.json", FormatSARIF); got != "out/scan.sarif" {
+		t.Fatalf("unexpected path %q", got)
+	}
+	if got := FormatPath("scan", FormatHTML); got != "scan.html" {
+		t.Fatalf("unexpected path %q", got)
+	}
+}
+
+func TestPrinterWritesSeveralFormats(t *testing.T) {
+	dir := t.TempDir()
+	printer := NewPrinter(true, true, false, "")
+	for _, format := range []string{FormatJSON, FormatSARIF, FormatMarkdown, FormatHTML} {
+		if err := printer.AddOutput(format, FormatPath(filepath.Join(dir, "scan"), format)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := printer.AddOutput("pdf", filepath.Join(dir, "scan.pdf")); err == nil {
+		t.Fatal("expected unknown format to be rejected")
+	}
+
+	printer.Result(common.ScanResult{URL: "https://example.com/admin/", Target: "https://example.com/admin", StatusCode: 200,
+		Module: "bypass", Detail: "path -> status 403 -> 200", Reason: "status 403 -> 200", Severity: common.SeverityMedium})
+	printer.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "scan.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []common.ScanResult
+	if err := json.Unmarshal(data, &results); err != nil || len(results) != 1 {
+		t.Fatalf("unexpected JSON output %q: %v", data, err)
+	}
+
+	for name, want := range map[string]string{
+		"scan.sarif": `"ruleId": "httpsuite/bypass"`,
+		"scan.md":    "### https://example.com/admin",
+		"scan.html":  "status 403 -&gt; 200",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("expected %q in %s:\n%s", want, name, data)
+		}
+	}
+}
+
+func TestJSONLinesFileIsValidBeforeClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.jsonl")
+	printer := NewPrinter(true, true, true, path)
+	defer printer.Close()
+
+	first := common.ScanResult{URL: "https://example.com/admin", Method: "GET", StatusCode: 403, Module: "bypass", Detail: "default request"}
+	printer.Result(first)
+	printer.Result(common.ScanResult{URL: "https://example.com/", StatusCode: 200, Module: "cors", Detail: "wildcard", Vulnerable: true})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", data)
+	}
+	var r common.ScanResult
+	if err := json.Unmarshal([]byte(lines[0]), &r); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[0], err)
+	}
+	if r.SchemaVersion != common.SchemaVersion || r.ID != first.FindingID() || r.Timestamp.IsZero() {
+		t.Fatalf("record not stamped: %+v", r)
+	}
+}
+
+func TestMinSeverityDropsLowerResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.jsonl")
+	printer := NewPrinter(true, true, true, path)
+	printer.SetMinSeverity(common.SeverityMedium)
+
+	printer.Result(common.ScanResult{URL: "https://example.com/admin", Module: "bypass", Detail: "default request"})
+	printer.Result(common.ScanResult{URL: "https://example.com/", Module: "cors", Severity: common.SeverityLow})
+	printer.Result(common.ScanResult{URL: "https://example.com/", Module: "cors", Severity: common.SeverityCritical})
+	printer.Result(common.ScanResult{URL: "https://example.com/", Module: "crlf", Vulnerable: true})
+	printer.Close()
+
+	if total, _ := printer.Stats(); total != 2 {
+		t.Fatalf("expected 2 results above the threshold, got %d", total)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("expected 2 records, got %q", data)
+	}
+}
+
+func TestReadResultsAcceptsJSONAndJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	printer := NewPrinter(true, true, false, "")
+	for _, format := range []string{FormatJSON, FormatJSONL} {
+		if err := printer.AddOutput(format, FormatPath(filepath.Join(dir, "scan"), format)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	printer.Result(common.ScanResult{URL: "https://example.com/admin", Target: "https://example.com/admin", StatusCode: 200, Module: "bypass",
+		Technique: "header bypass", Payload: "X-Original-URL: /admin", Headers: map[string]string{"X-Original-URL": "/admin"}})
+	printer.Result(common.ScanResult{URL: "https://example.com/admin/.", Target: "https://example.com/admin", StatusCode: 200, Module: "bypass",
+		Technique: "endpath", Payload: "/."})
+	printer.Close()
+
+	for _, format := range []string{FormatJSON, FormatJSONL} {
+		results, err := ReadResults(FormatPath(filepath.Join(dir, "scan"), format))
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(results) != 2 || results[0].Headers["X-Original-URL"] != "/admin" || results[1].Payload != "/." || results[0].ID == "" {
+			t.Fatalf("%s: unexpected results %+v", format, results)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.jsonl")
+	if err := os.WriteFile(bad, []byte("{\"url\":\"x\"}\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadResults(bad); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Fatalf("expected an error naming line 2, got %v", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/aether-0/httpsuite/pkg/common"
 )
@@ -25,14 +27,23 @@ const (
 
 // Printer handles all output formatting
 type Printer struct {
-	mu                sync.Mutex
-	silent            bool
-	noColor           bool
-	jsonMode          bool
-	outFile           *os.File
-	jsonFileHasResult bool
-	totalResults      int
-	vulnResults       int
+	mu              sync.Mutex
+	silent          bool
+	noColor         bool
+	jsonMode        bool
+	files           []*fileSink
+	reports         []reportSink
+	results         []common.ScanResult
+	started         time.Time
+	minSeverity     string
+	unique          bool
+	clusters        map[string]*cluster
+	clusterOrder    []*cluster
+	totalResults    int
+	vulnResults     int
+	progress        *Progress
+	progressEnabled bool
+	progressShown   bool
 }
 
 // NewPrinter creates a new Printer instance
@@ -41,37 +52,46 @@ func NewPrinter(silent, noColor, jsonMode bool, outputFile string) *Printer {
 		silent:   silent,
 		noColor:  noColor,
 		jsonMode: jsonMode,
+		// The status line shares the terminal with results, so only show it
+		// for interactive, human-readable runs.
+		progressEnabled: !silent && !jsonMode && isTerminal(os.Stderr),
 	}
+	p.started = time.Now()
 	if outputFile != "" {
-		f, err := os.Create(outputFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
-		} else {
-			p.outFile = f
-			if p.jsonMode {
-				if _, err := p.outFile.WriteString("[\n"); err != nil {
-					fmt.Fprintf(os.Stderr, "Error initializing JSON output file: %v\n", err)
-					p.outFile.Close()
-					p.outFile = nil
-				}
+		format := FormatText
+		if jsonMode {
+			format = FormatJSON
+			if filepath.Ext(outputFile) == formatExtensions[FormatJSONL] {
+				format = FormatJSONL
 			}
 		}
+		if err := p.AddOutput(format, outputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+		}
 	}
 	return p
 }
 
-// Close cleans up resources
+// Close summarizes clustered results, finishes streamed output files and
+// writes the collected reports
 func (p *Printer) Close() {
-	if p.outFile != nil {
-		if p.jsonMode {
-			if p.jsonFileHasResult {
-				p.outFile.WriteString("\n]\n")
-			} else {
-				p.outFile.WriteString("]\n")
-			}
-		}
-		p.outFile.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.writeClustersLocked()
+	for _, f := range p.files {
+		f.close()
 	}
+	p.files = nil
+	p.writeReportsLocked()
+}
+
+// SetMinSeverity drops results below severity from every output. An empty
+// severity keeps everything.
+func (p *Printer) SetMinSeverity(severity string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.minSeverity = severity
 }
 
 // colorForStatus returns the color code for a given HTTP status code
@@ -93,6 +113,29 @@ func (p *Printer) colorForStatus(code int) string {
 	}
 }
 
+// colorForSeverity returns the color code for a severity level
+func (p *Printer) colorForSeverity(severity string) string {
+	if p.noColor {
+		return ""
+	}
+	switch severity {
+	case common.SeverityCritical, common.SeverityHigh:
+		return Red
+	case common.SeverityMedium:
+		return Yellow
+	default:
+		return Cyan
+	}
+}
+
+// severityLabel is "medium" or, when the module rated it, "medium/firm"
+func severityLabel(r common.ScanResult) string {
+	if r.Confidence == "" {
+		return r.Severity
+	}
+	return r.Severity + "/" + r.Confidence
+}
+
 func (p *Printer) reset() string {
 	if p.noColor {
 		return ""
@@ -154,11 +197,11 @@ func (p *Printer) Banner() {
 |  _  | | |   | | |  __/ ___) | |_| || |  | | | |___
 |_| |_| |_|   |_| |_|   |____/ \___/|___| |_| |_____|
 
-           %shttpsuite v1.0%s
+           %shttpsuite v%s%s
   %sSmart HTTP Security Testing for Pentest and Bug Bounty Work%s
   %sBypass • CRLF • CORS • Methods • Smuggle • Sync%s
 
-%s`, p.cyan(), p.bold(), p.reset(), p.green(), p.reset(), p.dim(), p.reset(), p.reset())
+%s`, p.cyan(), p.bold(), common.Version, p.reset(), p.green(), p.reset(), p.dim(), p.reset(), p.reset())
 }
 
 // Info prints an info message
@@ -168,6 +211,8 @@ func (p *Printer) Info(format string, args ...interface{}) {
 	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
 	fmt.Printf("%s[INF]%s %s\n", p.cyan(), p.reset(), fmt.Sprintf(format, args...))
 }
 
@@ -175,6 +220,8 @@ func (p *Printer) Info(format string, args ...interface{}) {
 func (p *Printer) Error(format string, args ...interface{}) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
 	fmt.Fprintf(os.Stderr, "%s[ERR]%s %s\n", p.red(), p.reset(), fmt.Sprintf(format, args...))
 }
 
@@ -185,6 +232,8 @@ func (p *Printer) Success(format string, args ...interface{}) {
 	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
 	fmt.Printf("%s[OK]%s %s\n", p.green(), p.reset(), fmt.Sprintf(format, args...))
 }
 
@@ -195,6 +244,8 @@ func (p *Printer) Warning(format string, args ...interface{}) {
 	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
 	fmt.Printf("%s[WRN]%s %s\n", Yellow, p.reset(), fmt.Sprintf(format, args...))
 }
 
@@ -205,6 +256,8 @@ func (p *Printer) SectionHeader(title string) {
 	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
 	fmt.Printf("\n%s━━━━━━━━━━━━━━ %s ━━━━━━━━━━━━━━%s\n", p.magenta(), title, p.reset())
 }
 
@@ -212,28 +265,80 @@ func (p *Printer) SectionHeader(title string) {
 func (p *Printer) Result(r common.ScanResult) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	if p.minSeverity != "" && common.SeverityRank(r.EffectiveSeverity()) < common.SeverityRank(p.minSeverity) {
+		return
+	}
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
 
 	p.totalResults++
 	if r.Vulnerable {
 		p.vulnResults++
 	}
+	if p.progress != nil {
+		p.progress.findings.Add(1)
+	}
+
+	if p.unique && p.joinClusterLocked(r) {
+		return
+	}
+	written := p.writeLocked(r)
+	if p.unique {
+		p.startClusterLocked(written)
+	}
+}
+
+// Preflight prints what the pre-flight phase learned about a host. It is
+// written like a result but not counted as one.
+func (p *Printer) Preflight(info common.Host) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clearProgressLocked()
+	defer p.drawProgressLocked()
+
+	p.writeLocked(common.ScanResult{
+		URL:        info.Origin,
+		StatusCode: info.StatusCode,
+		Detail:     info.Summary(),
+		Module:     "preflight",
+		Host:       &info,
+	})
+}
+
+// writeLocked stamps r with its schema version, ID and time, then prints
+// it to stdout and the output files. p.mu must be held.
+func (p *Printer) writeLocked(r common.ScanResult) common.ScanResult {
+	r.SchemaVersion = common.SchemaVersion
+	if r.ID == "" {
+		r.ID = r.FindingID()
+	}
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+	r.Severity = r.EffectiveSeverity()
+
+	p.emitLocked(r)
+	if len(p.reports) > 0 {
+		p.results = append(p.results, r)
+	}
+	return r
+}
 
+// emitLocked prints an already stamped r. p.mu must be held.
+func (p *Printer) emitLocked(r common.ScanResult) {
 	if p.jsonMode {
 		data, _ := json.Marshal(r)
 		fmt.Println(string(data))
-		if p.outFile != nil {
-			if p.jsonFileHasResult {
-				p.outFile.WriteString(",\n")
-			}
-			p.outFile.Write(data)
-			p.jsonFileHasResult = true
-		}
 	} else {
 		color := p.colorForStatus(r.StatusCode)
 		vuln := ""
 		if r.Vulnerable {
 			vuln = fmt.Sprintf(" %s[VULNERABLE]%s", p.green(), p.reset())
 		}
+		severity := ""
+		if r.Severity != common.SeverityInfo {
+			severity = fmt.Sprintf(" %s[%s]%s", p.colorForSeverity(r.Severity), severityLabel(r), p.reset())
+		}
 		module := fmt.Sprintf("%s[%s]%s", Dim, r.Module, p.reset())
 		detail := ""
 		if r.Detail != "" {
@@ -243,28 +348,21 @@ func (p *Printer) Result(r common.ScanResult) {
 		if r.Method != "" {
 			method = fmt.Sprintf(" %s", r.Method)
 		}
-		fmt.Printf("%s%d%s%s %s %d bytes%s%s %s\n",
+		fmt.Printf("%s%d%s%s %s %d bytes%s%s%s %s%s\n",
 			color, r.StatusCode, p.reset(),
 			method,
 			r.URL,
 			r.ContentLength,
 			detail,
 			vuln,
+			severity,
 			module,
+			clusterSuffix(r),
 		)
 	}
 
-	// Write to file if configured (non-JSON mode writes line by line)
-	if p.outFile != nil && !p.jsonMode {
-		line := fmt.Sprintf("%d %s %s %d bytes", r.StatusCode, r.Method, r.URL, r.ContentLength)
-		if r.Detail != "" {
-			line += " (" + r.Detail + ")"
-		}
-		if r.Vulnerable {
-			line += " [VULNERABLE]"
-		}
-		line += " [" + r.Module + "]"
-		fmt.Fprintln(p.outFile, line)
+	for _, f := range p.files {
+		f.write(r)
 	}
 }
 
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const progressInterval = 250 * time.Millisecond
+
+// Progress tracks request counters for a single module run and renders them
+// as a status line on stderr while results stream to stdout.
+type Progress struct {
+	printer  *Printer
+	module   string
+	start    time.Time
+	total    atomic.Int64
+	sent     atomic.Int64
+	errors   atomic.Int64
+	findings atomic.Int64
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// StartProgress begins tracking a module run. The returned tracker is always
+// usable; when progress display is disabled it only counts.
+func (p *Printer) StartProgress(module string) *Progress {
+	pr := &Progress{
+		printer: p,
+		module:  module,
+		start:   time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	if p.progress != nil {
+		p.clearProgressLocked()
+	}
+	p.progress = pr
+	enabled := p.progressEnabled
+	p.mu.Unlock()
+
+	if !enabled {
+		close(pr.done)
+		return pr
+	}
+
+	go pr.loop()
+	return pr
+}
+
+// DisableProgress turns off the live progress display.
+func (p *Printer) DisableProgress() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progressEnabled = false
+}
+
+// AddTotal increases the number of requests expected for this run.
+func (pr *Progress) AddTotal(n int) {
+	if pr == nil || n <= 0 {
+		return
+	}
+	pr.total.Add(int64(n))
+}
+
+// Request records one completed request and whether it failed.
+func (pr *Progress) Request(err error) {
+	if pr == nil {
+		return
+	}
+	pr.sent.Add(1)
+	if err != nil {
+		pr.errors.Add(1)
+	}
+}
+
+// Counts returns the requests sent, errors, and findings recorded so far.
+func (pr *Progress) Counts() (sent, errors, findings int64) {
+	if pr == nil {
+		return 0, 0, 0
+	}
+	return pr.sent.Load(), pr.errors.Load(), pr.findings.Load()
+}
+
+// Finish stops rendering and removes the status line.
+func (pr *Progress) Finish() {
+	if pr == nil {
+		return
+	}
+
+	select {
+	case <-pr.stop:
+	default:
+		close(pr.stop)
+	}
+	<-pr.done
+
+	p := pr.printer
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.progress == pr {
+		p.clearProgressLocked()
+		p.progress = nil
+	}
+}
+
+func (pr *Progress) loop() {
+	defer close(pr.done)
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pr.stop:
+			return
+		case <-ticker.C:
+			p := pr.printer
+			p.mu.Lock()
+			if p.progress == pr {
+				p.drawProgressLocked()
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (pr *Progress) line() string {
+	total := pr.total.Load()
+	sent := pr.sent.Load()
+	elapsed := time.Since(pr.start).Seconds()
+
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(sent) / elapsed
+	}
+
+	eta := "--"
+	if total > sent && rate > 0 {
+		remaining := time.Duration(float64(total-sent)/rate) * time.Second
+		eta = remaining.Round(time.Second).String()
+	} else if total > 0 && sent >= total {
+		eta = "0s"
+	}
+
+	counter := fmt.Sprintf("%d", sent)
+	if total > 0 {
+		counter = fmt.Sprintf("%d/%d", sent, total)
+	}
+
+	return fmt.Sprintf("[%s] %s req | %d err | %d found | %.1f req/s | ETA %s",
+		pr.module, counter, pr.errors.Load(), pr.findings.Load(), rate, eta)
+}
+
+// clearProgressLocked erases the status line. Callers must hold p.mu.
+func (p *Printer) clearProgressLocked() {
+	if !p.progressShown {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+	p.progressShown = false
+}
+
+// drawProgressLocked renders the active status line. Callers must hold p.mu.
+func (p *Printer) drawProgressLocked() {
+	if p.progress == nil || !p.progressEnabled {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s%s%s", p.dim(), p.progress.line(), p.reset())
+	p.progressShown = true
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+func TestProgressCountsRequestsAndFindings(t *testing.T) {
+	printer := NewPrinter(true, true, false, "")
+	progress := printer.StartProgress("bypass")
+	progress.AddTotal(3)
+
+	progress.Request(nil)
+	progress.Request(errors.New("timeout"))
+	printer.Result(common.ScanResult{URL: "https://example.com/admin", StatusCode: 200, Module: "bypass"})
+
+	sent, errCount, findings := progress.Counts()
+	if sent != 2 || errCount != 1 || findings != 1 {
+		t.Fatalf("unexpected counts: sent=%d errors=%d findings=%d", sent, errCount, findings)
+	}
+
+	line := progress.line()
+	for _, want := range []string{"[bypass]", "2/3 req", "1 err", "1 found"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %q in progress line %q", want, line)
+		}
+	}
+
+	progress.Finish()
+	progress.Finish()
+}
+
+func TestProgressDisabledInSilentMode(t *testing.T) {
+	printer := NewPrinter(true, true, false, "")
+	if printer.progressEnabled {
+		t.Fatalf("expected progress to be disabled in silent mode")
+	}
+
+	printer = NewPrinter(false, true, true, "")
+	if printer.progressEnabled {
+		t.Fatalf("expected progress to be disabled in JSON mode")
+	}
+}
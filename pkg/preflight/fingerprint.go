@@ -0,0 +1,85 @@
+package preflight
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// wafSignature identifies a WAF or CDN by a header, cookie or body marker.
+// Header and cookie names are matched case-insensitively; values by substring.
+type wafSignature struct {
+	name   string
+	header string
+	value  string
+	cookie string
+	body   string
+}
+
+var wafSignatures = []wafSignature{
+	{name: "Cloudflare", header: "Cf-Ray"},
+	{name: "Cloudflare", header: "Server", value: "cloudflare"},
+	{name: "Akamai", header: "Server", value: "akamaighost"},
+	{name: "Akamai", header: "X-Akamai-Transformed"},
+	{name: "AWS CloudFront", header: "X-Amz-Cf-Id"},
+	{name: "AWS WAF", cookie: "aws-waf-token"},
+	{name: "AWS ELB", header: "Server", value: "awselb"},
+	{name: "Imperva", header: "X-Iinfo"},
+	{name: "Imperva", cookie: "incap_ses"},
+	{name: "Sucuri", header: "X-Sucuri-Id"},
+	{name: "F5 BIG-IP", cookie: "bigipserver"},
+	{name: "F5 BIG-IP", header: "X-Wa-Info"},
+	{name: "Fastly", header: "X-Fastly-Request-Id"},
+	{name: "Azure Front Door", header: "X-Azure-Ref"},
+	{name: "Barracuda", cookie: "barra_counter_session"},
+	{name: "FortiWeb", cookie: "fortiwafsid"},
+	{name: "ModSecurity", header: "Server", value: "mod_security"},
+	{name: "ModSecurity", body: "mod_security"},
+	{name: "Wordfence", body: "generated by wordfence"},
+}
+
+// parkedMarkers appear on domain parking and for-sale pages
+var parkedMarkers = []string{
+	"this domain is for sale",
+	"this domain may be for sale",
+	"buy this domain",
+	"domain is parked",
+	"parked free",
+	"parkingcrew",
+	"sedoparking",
+	"bodis.com",
+}
+
+func detectWAF(header http.Header, body []byte) string {
+	lowerBody := bytes.ToLower(body)
+	for _, sig := range wafSignatures {
+		switch {
+		case sig.header != "":
+			value := header.Get(sig.header)
+			if value != "" && strings.Contains(strings.ToLower(value), sig.value) {
+				return sig.name
+			}
+		case sig.cookie != "":
+			for _, cookie := range header.Values("Set-Cookie") {
+				if strings.HasPrefix(strings.ToLower(cookie), sig.cookie) {
+					return sig.name
+				}
+			}
+		case sig.body != "":
+			if bytes.Contains(lowerBody, []byte(sig.body)) {
+				return sig.name
+			}
+		}
+	}
+	return ""
+}
+
+func isParked(body []byte) bool {
+	lowerBody := bytes.ToLower(body)
+	for _, marker := range parkedMarkers {
+		if bytes.Contains(lowerBody, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
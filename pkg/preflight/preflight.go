@@ -0,0 +1,199 @@
+package preflight
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+	"github.com/aether-0/httpsuite/pkg/scope"
+)
+
+// maxSampleBytes bounds how much of a probe response body is inspected
+const maxSampleBytes = 32 * 1024
+
+// Results caches pre-flight information per origin and the base-request
+// status of every checked target. A nil *Results knows nothing.
+type Results struct {
+	client *httpclient.Client
+
+	mu       sync.Mutex
+	hosts    map[string]*hostEntry
+	statuses map[string]int
+}
+
+type hostEntry struct {
+	once sync.Once
+	info common.Host
+}
+
+// New creates an empty cache that probes through client
+func New(client *httpclient.Client) *Results {
+	return &Results{
+		client:   client,
+		hosts:    make(map[string]*hostEntry),
+		statuses: make(map[string]int),
+	}
+}
+
+// Check sends the target's base request and probes its origin once. It
+// returns the origin's information.
+func (r *Results) Check(method, targetURL string, headers map[string]string, body []byte) common.Host {
+	origin := originOf(targetURL)
+
+	r.mu.Lock()
+	entry, ok := r.hosts[origin]
+	if !ok {
+		entry = &hostEntry{}
+		r.hosts[origin] = entry
+	}
+	r.mu.Unlock()
+
+	entry.once.Do(func() { entry.info = r.probeOrigin(origin, targetURL) })
+	if !entry.info.Alive {
+		return entry.info
+	}
+
+	summary, err := r.client.InspectRequestBody(method, targetURL, headers, body)
+	if err == nil {
+		r.mu.Lock()
+		r.statuses[targetURL] = summary.StatusCode
+		r.mu.Unlock()
+	}
+	return entry.info
+}
+
+// Host returns the cached information for targetURL's origin
+func (r *Results) Host(targetURL string) (common.Host, bool) {
+	if r == nil {
+		return common.Host{}, false
+	}
+	r.mu.Lock()
+	entry, ok := r.hosts[originOf(targetURL)]
+	r.mu.Unlock()
+	if !ok {
+		return common.Host{}, false
+	}
+	return entry.info, true
+}
+
+// Status returns the base-request status code recorded for targetURL
+func (r *Results) Status(targetURL string) (int, bool) {
+	if r == nil {
+		return 0, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status, ok := r.statuses[targetURL]
+	return status, ok
+}
+
+// Hosts returns every probed origin, sorted
+func (r *Results) Hosts() []common.Host {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	infos := make([]common.Host, 0, len(r.hosts))
+	for _, entry := range r.hosts {
+		infos = append(infos, entry.info)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Origin < infos[j].Origin })
+	return infos
+}
+
+func (r *Results) probeOrigin(origin, targetURL string) common.Host {
+	info := common.Host{Origin: origin}
+
+	resp, sample, err := r.get(origin + "/")
+	if errors.Is(err, scope.ErrOutOfScope) {
+		// Path rules can exclude the root; fingerprint the target instead
+		resp, sample, err = r.get(targetURL)
+	}
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Alive = true
+	info.StatusCode = resp.StatusCode
+	info.Server = resp.Header.Get("Server")
+	info.PoweredBy = resp.Header.Get("X-Powered-By")
+	info.WAF = detectWAF(resp.Header, sample)
+	info.Parked = isParked(sample)
+
+	// A random path shows how the server answers for content that cannot exist
+	if missing, missingSample, err := r.get(origin + "/" + randomToken() + ".html"); err == nil {
+		info.NotFoundStatus = missing.StatusCode
+		info.Wildcard404 = missing.StatusCode != http.StatusNotFound && missing.StatusCode != http.StatusGone
+		if info.WAF == "" {
+			info.WAF = detectWAF(missing.Header, missingSample)
+		}
+	}
+
+	if strings.HasPrefix(origin, "https://") {
+		r.probeTLS(origin, &info)
+	}
+	return info
+}
+
+// probeTLS records the TLS version and whether h2 is offered over ALPN
+func (r *Results) probeTLS(origin string, info *common.Host) {
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return
+	}
+	conn, err := r.client.Dial(parsed, &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2", "http/1.1"},
+	})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		info.TLS = true
+		info.TLSVersion = tls.VersionName(state.Version)
+		info.HTTP2 = state.NegotiatedProtocol == "h2"
+	}
+}
+
+func (r *Results) get(targetURL string) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	sample, _ := io.ReadAll(io.LimitReader(resp.Body, maxSampleBytes))
+	return resp, sample, nil
+}
+
+func originOf(targetURL string) string {
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return targetURL
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+func randomToken() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "httpsuite-" + hex.EncodeToString(buf)
+}
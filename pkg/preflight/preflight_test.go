@@ -0,0 +1,109 @@
+package preflight
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/httpclient"
+)
+
+func newTestResults() *Results {
+	return New(httpclient.New(httpclient.Options{Timeout: 2 * time.Second, Insecure: true}))
+}
+
+func TestCheckFingerprintsTLSOrigin(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx")
+		w.Header().Set("Cf-Ray", "8a1b2c3d4e5f-AMS")
+		switch r.URL.Path {
+		case "/", "/admin":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			// Soft 404: every path answers 200
+			w.Write([]byte("<html>home</html>"))
+		}
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	results := newTestResults()
+	info := results.Check(http.MethodGet, server.URL+"/admin", nil, nil)
+
+	if !info.Alive || info.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected a live origin answering 403, got %+v", info)
+	}
+	if !info.TLS || !info.HTTP2 || !strings.HasPrefix(info.TLSVersion, "TLS") {
+		t.Fatalf("expected TLS with h2, got %+v", info)
+	}
+	if info.Server != "nginx" || info.WAF != "Cloudflare" {
+		t.Fatalf("unexpected fingerprint %+v", info)
+	}
+	if !info.Wildcard404 || info.NotFoundStatus != http.StatusOK {
+		t.Fatalf("expected wildcard 404 behaviour, got %+v", info)
+	}
+
+	if status, ok := results.Status(server.URL + "/admin"); !ok || status != http.StatusForbidden {
+		t.Fatalf("expected the target status to be cached, got %d %v", status, ok)
+	}
+	if hosts := results.Hosts(); len(hosts) != 1 || hosts[0].Origin != server.URL {
+		t.Fatalf("expected one cached origin, got %+v", hosts)
+	}
+}
+
+func TestCheckProbesEachOriginOnce(t *testing.T) {
+	var rootHits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			rootHits++
+		}
+		if strings.HasPrefix(r.URL.Path, "/httpsuite-") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("This domain is for sale!"))
+	}))
+	defer server.Close()
+
+	results := newTestResults()
+	results.Check(http.MethodGet, server.URL+"/a", nil, nil)
+	info := results.Check(http.MethodGet, server.URL+"/b", nil, nil)
+
+	if rootHits != 1 {
+		t.Fatalf("expected the origin to be probed once, got %d root requests", rootHits)
+	}
+	if info.TLS || info.HTTP2 || info.Wildcard404 || !info.Parked {
+		t.Fatalf("unexpected plain HTTP fingerprint %+v", info)
+	}
+}
+
+func TestCheckMarksDeadHosts(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	deadURL := server.URL + "/admin"
+	server.Close()
+
+	results := newTestResults()
+	info := results.Check(http.MethodGet, deadURL, nil, nil)
+	if info.Alive || info.Error == "" {
+		t.Fatalf("expected a dead host, got %+v", info)
+	}
+	if _, ok := results.Status(deadURL); ok {
+		t.Fatalf("expected no status for a dead host")
+	}
+	if !strings.HasPrefix(info.Summary(), "dead:") {
+		t.Fatalf("unexpected summary %q", info.Summary())
+	}
+}
+
+func TestNilResultsKnowNothing(t *testing.T) {
+	var results *Results
+	if _, ok := results.Host("https://example.com/"); ok {
+		t.Fatalf("expected a nil cache to have no hosts")
+	}
+	if _, ok := results.Status("https://example.com/"); ok {
+		t.Fatalf("expected a nil cache to have no statuses")
+	}
+}
@@ -0,0 +1,167 @@
+package rawrequest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http/httputil"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Request is a base request parsed from a raw HTTP request file, as saved
+// from Burp ("Copy to file") or captured off the wire.
+type Request struct {
+	Method      string
+	URL         string
+	Headers     map[string]string
+	Body        []byte
+	ContentType string
+}
+
+// hopHeaders are managed by the HTTP client and dropped from parsed requests.
+// Accept-Encoding is dropped so responses stay readable for fingerprinting.
+var hopHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Proxy-Connection":  true,
+	"Keep-Alive":        true,
+	"Accept-Encoding":   true,
+}
+
+// Load reads and parses a raw HTTP request file. Origin-form request lines
+// are resolved against the Host header using scheme.
+func Load(path, scheme string) (*Request, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request file: %w", err)
+	}
+	req, err := Parse(data, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing request file %s: %w", path, err)
+	}
+	return req, nil
+}
+
+// Parse parses a raw HTTP/1.x request. The body is everything after the blank
+// line, cut to Content-Length when present and de-chunked when needed.
+func Parse(data []byte, scheme string) (*Request, error) {
+	head, body := splitHeadBody(data)
+
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(head)))
+	requestLine, err := reader.ReadLine()
+	for err == nil && strings.TrimSpace(requestLine) == "" {
+		requestLine, err = reader.ReadLine()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("missing request line")
+	}
+
+	fields := strings.Fields(requestLine)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("malformed request line %q", requestLine)
+	}
+	method, target := strings.ToUpper(fields[0]), fields[1]
+
+	mimeHeader, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("malformed headers: %w", err)
+	}
+
+	targetURL, err := resolveTarget(target, mimeHeader.Get("Host"), scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = decodeBody(body, mimeHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &Request{
+		Method:  method,
+		URL:     targetURL,
+		Headers: make(map[string]string),
+	}
+	for name, values := range mimeHeader {
+		switch {
+		case hopHeaders[name]:
+			continue
+		case name == "Content-Type":
+			req.ContentType = values[0]
+		case name == "Cookie":
+			req.Headers[name] = strings.Join(values, "; ")
+		default:
+			req.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	if len(body) > 0 {
+		req.Body = body
+	}
+
+	return req, nil
+}
+
+// splitHeadBody splits at the first empty line, accepting CRLF or bare LF.
+func splitHeadBody(data []byte) ([]byte, []byte) {
+	crlf := bytes.Index(data, []byte("\r\n\r\n"))
+	lf := bytes.Index(data, []byte("\n\n"))
+
+	switch {
+	case crlf >= 0 && (lf < 0 || crlf < lf):
+		return data[:crlf+4], data[crlf+4:]
+	case lf >= 0:
+		return data[:lf+2], data[lf+2:]
+	default:
+		return data, nil
+	}
+}
+
+func resolveTarget(target, host, scheme string) (string, error) {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		parsed, err := url.Parse(target)
+		if err != nil || parsed.Host == "" {
+			return "", fmt.Errorf("invalid request target %q", target)
+		}
+		return parsed.String(), nil
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("request has no Host header and no absolute URL")
+	}
+	if !strings.HasPrefix(target, "/") {
+		return "", fmt.Errorf("unsupported request target %q", target)
+	}
+
+	if scheme == "" {
+		scheme = "https"
+		if strings.HasSuffix(host, ":80") {
+			scheme = "http"
+		}
+	}
+	return scheme + "://" + host + target, nil
+}
+
+func decodeBody(body []byte, header textproto.MIMEHeader) ([]byte, error) {
+	if strings.EqualFold(header.Get("Transfer-Encoding"), "chunked") {
+		decoded, err := io.ReadAll(httputil.NewChunkedReader(bytes.NewReader(body)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunked body: %w", err)
+		}
+		return decoded, nil
+	}
+
+	if value := header.Get("Content-Length"); value != "" {
+		length, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil && length >= 0 && length < len(body) {
+			return body[:length], nil
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,84 @@
+package rawrequest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBurpRequest(t *testing.T) {
+	raw := strings.Join([]string{
+		"POST /api/v1/users?id=7 HTTP/1.1",
+		"Host: app.example.com",
+		"User-Agent: Mozilla/5.0",
+		"Cookie: sid=abc",
+		"Content-Type: application/json",
+		"Content-Length: 16",
+		"Accept-Encoding: gzip, deflate",
+		"",
+		`{"role":"admin"}`,
+		"",
+	}, "\r\n")
+
+	req, err := Parse([]byte(raw), "")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if req.Method != "POST" || req.URL != "https://app.example.com/api/v1/users?id=7" {
+		t.Fatalf("unexpected request line: %s %s", req.Method, req.URL)
+	}
+	if string(req.Body) != `{"role":"admin"}` {
+		t.Fatalf("unexpected body %q", req.Body)
+	}
+	if req.ContentType != "application/json" {
+		t.Fatalf("unexpected content type %q", req.ContentType)
+	}
+	if req.Headers["Cookie"] != "sid=abc" || req.Headers["User-Agent"] != "Mozilla/5.0" {
+		t.Fatalf("unexpected headers: %v", req.Headers)
+	}
+	for _, dropped := range []string{"Host", "Content-Length", "Accept-Encoding", "Content-Type"} {
+		if _, ok := req.Headers[dropped]; ok {
+			t.Fatalf("expected %s to be dropped from headers", dropped)
+		}
+	}
+}
+
+func TestParseHandlesLFAbsoluteFormAndChunkedBodies(t *testing.T) {
+	raw := "PUT http://internal.example.com:8080/upload HTTP/1.1\n" +
+		"Host: internal.example.com:8080\n" +
+		"Transfer-Encoding: chunked\n" +
+		"\n" +
+		"5\r\nhello\r\n0\r\n\r\n"
+
+	req, err := Parse([]byte(raw), "")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if req.URL != "http://internal.example.com:8080/upload" {
+		t.Fatalf("unexpected URL %q", req.URL)
+	}
+	if string(req.Body) != "hello" {
+		t.Fatalf("unexpected body %q", req.Body)
+	}
+
+	plain, err := Parse([]byte("GET / HTTP/1.1\nHost: example.com:80\n"), "")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if plain.URL != "http://example.com:80/" || plain.Body != nil {
+		t.Fatalf("unexpected request: %+v", plain)
+	}
+}
+
+func TestParseRejectsMalformedRequests(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"GARBAGE\r\n\r\n",
+		"GET /admin HTTP/1.1\r\nAccept: */*\r\n\r\n",
+		"GET admin HTTP/1.1\r\nHost: example.com\r\n\r\n",
+	} {
+		if _, err := Parse([]byte(raw), ""); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
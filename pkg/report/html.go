@@ -0,0 +1,86 @@
+package report
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// htmlTemplate is self-contained: styles are inline and nothing is fetched
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"severity": Severity,
+	"label":    label,
+	"reason":   reason,
+	"evidence": evidence,
+	"yesNo":    yesNo,
+	"notFound": notFoundText,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Meta.Tool}} report</title>
+<style>
+body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;margin:2rem;color:#1f2328;background:#fff}
+h1{margin-bottom:.2rem}
+.meta{color:#656d76;margin-bottom:1.5rem}
+table{border-collapse:collapse;width:100%;margin:.5rem 0 1.5rem;font-size:.9rem}
+th,td{border:1px solid #d0d7de;padding:.35rem .5rem;text-align:left;vertical-align:top}
+th{background:#f6f8fa}
+td.url{word-break:break-all}
+code{font-size:.8rem;color:#57606a}
+details{border:1px solid #d0d7de;border-radius:6px;padding:.5rem 1rem;margin-bottom:1rem}
+summary{cursor:pointer;font-weight:600}
+.sev{display:inline-block;padding:0 .45rem;border-radius:1rem;color:#fff;font-size:.8rem}
+.sev-critical{background:#8b0000}.sev-high{background:#cf222e}.sev-medium{background:#bc4c00}
+.sev-low{background:#9a6700}.sev-info{background:#6e7781}
+</style>
+</head>
+<body>
+<h1>{{.Meta.Tool}} report</h1>
+<div class="meta">{{if not .Meta.Started.IsZero}}Scan from {{.Meta.Started.Format "2006-01-02 15:04:05"}} to {{.Meta.Finished.Format "2006-01-02 15:04:05"}}, {{end}}{{.Meta.Tool}} v{{.Meta.Version}}</div>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Severity</th><th>Results</th></tr>
+{{range .Counts}}<tr><td><span class="sev sev-{{.Severity}}">{{.Severity}}</span></td><td>{{.Count}}</td></tr>
+{{end}}</table>
+
+{{if .Hosts}}<h2>Hosts</h2>
+<table>
+<tr><th>Origin</th><th>Alive</th><th>TLS</th><th>HTTP/2</th><th>Server</th><th>WAF</th><th>Missing paths</th></tr>
+{{range .Hosts}}<tr><td class="url">{{.Origin}}</td><td>{{yesNo .Alive}}{{if .Error}} ({{.Error}}){{end}}</td><td>{{.TLSVersion}}</td><td>{{yesNo .HTTP2}}</td><td>{{.Server}}</td><td>{{.WAF}}</td><td>{{notFound .NotFoundStatus .Wildcard404}}</td></tr>
+{{end}}</table>
+{{end}}
+
+<h2>Results</h2>
+{{if not .Groups}}<p>No results.</p>{{end}}
+{{range .Groups}}<details open>
+<summary>{{.Target}}</summary>
+{{range .Modules}}<h3>{{.Module}}</h3>
+<table>
+<tr><th>Severity</th><th>Method</th><th>URL</th><th>Reason</th><th>Evidence</th><th>Fingerprint</th></tr>
+{{range .Results}}<tr><td><span class="sev sev-{{severity .}}">{{label .}}</span></td><td>{{.Method}}</td><td class="url">{{.URL}}</td><td>{{reason .}}</td><td>{{evidence .}}</td><td><code>{{.Fingerprint}}</code></td></tr>
+{{end}}</table>
+{{end}}</details>
+{{end}}
+</body>
+</html>
+`))
+
+type htmlData struct {
+	Meta   Meta
+	Counts []severityCount
+	Hosts  []common.Host
+	Groups []targetGroup
+}
+
+// WriteHTML writes a single-file HTML report grouped by target and module
+func WriteHTML(w io.Writer, results []common.ScanResult, meta Meta) error {
+	return htmlTemplate.Execute(w, htmlData{
+		Meta:   meta,
+		Counts: severityCounts(results),
+		Hosts:  hosts(results),
+		Groups: group(results),
+	})
+}
@@ -0,0 +1,99 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// WriteMarkdown writes a summary table, the pre-flight hosts and every
+// result grouped by target and module.
+func WriteMarkdown(w io.Writer, results []common.ScanResult, meta Meta) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "# %s report\n\n", meta.Tool)
+	if !meta.Started.IsZero() {
+		fmt.Fprintf(b, "Scan from %s to %s, %s v%s.\n\n",
+			meta.Started.Format("2006-01-02 15:04:05"), meta.Finished.Format("2006-01-02 15:04:05"), meta.Tool, meta.Version)
+	}
+
+	fmt.Fprintln(b, "## Summary")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "| Severity | Results |")
+	fmt.Fprintln(b, "|----------|---------|")
+	for _, c := range severityCounts(results) {
+		fmt.Fprintf(b, "| %s | %d |\n", c.Severity, c.Count)
+	}
+	fmt.Fprintln(b)
+
+	if infos := hosts(results); len(infos) > 0 {
+		fmt.Fprintln(b, "## Hosts")
+		fmt.Fprintln(b)
+		fmt.Fprintln(b, "| Origin | Alive | TLS | HTTP/2 | Server | WAF | Missing paths |")
+		fmt.Fprintln(b, "|--------|-------|-----|--------|--------|-----|---------------|")
+		for _, h := range infos {
+			fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				cell(h.Origin), yesNo(h.Alive), cell(h.TLSVersion), yesNo(h.HTTP2),
+				cell(h.Server), cell(h.WAF), cell(notFoundText(h.NotFoundStatus, h.Wildcard404)))
+		}
+		fmt.Fprintln(b)
+	}
+
+	fmt.Fprintln(b, "## Results")
+	fmt.Fprintln(b)
+	groups := group(results)
+	if len(groups) == 0 {
+		fmt.Fprintln(b, "No results.")
+	}
+	for _, g := range groups {
+		fmt.Fprintf(b, "### %s\n\n", cell(g.Target))
+		for _, m := range g.Modules {
+			fmt.Fprintf(b, "#### %s\n\n", m.Module)
+			fmt.Fprintln(b, "| Severity | Method | URL | Reason | Evidence | Fingerprint |")
+			fmt.Fprintln(b, "|----------|--------|-----|--------|----------|-------------|")
+			for _, r := range m.Results {
+				fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s |\n",
+					label(r), cell(r.Method), cell(r.URL), cell(reason(r)), cell(evidence(r)), code(r.Fingerprint))
+			}
+			fmt.Fprintln(b)
+		}
+	}
+
+	return b.Flush()
+}
+
+// cell escapes a value for a Markdown table cell
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "`", "") + "`"
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+func notFoundText(status int, wildcard bool) string {
+	switch {
+	case status == 0:
+		return ""
+	case wildcard:
+		return fmt.Sprintf("%d (wildcard)", status)
+	default:
+		return fmt.Sprintf("%d", status)
+	}
+}
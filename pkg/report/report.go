@@ -0,0 +1,169 @@
+package report
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+// Meta describes the run a report covers
+type Meta struct {
+	Tool     string
+	Version  string
+	Started  time.Time
+	Finished time.Time
+}
+
+// Severity returns the severity the module gave r
+func Severity(r common.ScanResult) string {
+	return r.EffectiveSeverity()
+}
+
+// label is the severity followed by the confidence, when there is one
+func label(r common.ScanResult) string {
+	if r.Confidence == "" {
+		return Severity(r)
+	}
+	return Severity(r) + " (" + r.Confidence + ")"
+}
+
+// IsFinding reports whether a result belongs in a findings list rather
+// than being context such as a baseline or pre-flight record.
+func IsFinding(r common.ScanResult) bool {
+	return r.Host == nil && Severity(r) != common.SeverityInfo
+}
+
+// targetGroup holds the results of one target, split by module
+type targetGroup struct {
+	Target  string
+	Modules []moduleGroup
+}
+
+type moduleGroup struct {
+	Module  string
+	Results []common.ScanResult
+}
+
+// targetOf falls back to the origin when a result does not name its target
+func targetOf(r common.ScanResult) string {
+	if r.Target != "" {
+		return r.Target
+	}
+	parsed, err := url.Parse(r.URL)
+	if err != nil || parsed.Host == "" {
+		return r.URL
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// group splits results by target and module in first-seen order, most
+// severe results first. Pre-flight records are left out.
+func group(results []common.ScanResult) []targetGroup {
+	var groups []targetGroup
+	index := make(map[string]int)
+	for _, r := range results {
+		if r.Host != nil {
+			continue
+		}
+		target := targetOf(r)
+		i, ok := index[target]
+		if !ok {
+			i = len(groups)
+			index[target] = i
+			groups = append(groups, targetGroup{Target: target})
+		}
+
+		g := &groups[i]
+		m := -1
+		for j := range g.Modules {
+			if g.Modules[j].Module == r.Module {
+				m = j
+				break
+			}
+		}
+		if m < 0 {
+			m = len(g.Modules)
+			g.Modules = append(g.Modules, moduleGroup{Module: r.Module})
+		}
+		g.Modules[m].Results = append(g.Modules[m].Results, r)
+	}
+
+	for _, g := range groups {
+		for _, m := range g.Modules {
+			sort.SliceStable(m.Results, func(a, b int) bool {
+				return common.SeverityRank(Severity(m.Results[a])) > common.SeverityRank(Severity(m.Results[b]))
+			})
+		}
+	}
+	return groups
+}
+
+// hosts returns the pre-flight records among results
+func hosts(results []common.ScanResult) []common.Host {
+	var infos []common.Host
+	for _, r := range results {
+		if r.Host != nil {
+			infos = append(infos, *r.Host)
+		}
+	}
+	return infos
+}
+
+// severityCounts counts results per severity, most severe first
+func severityCounts(results []common.ScanResult) []severityCount {
+	counts := make(map[string]int)
+	for _, r := range results {
+		if r.Host == nil {
+			counts[Severity(r)]++
+		}
+	}
+	out := make([]severityCount, 0, len(common.Severities))
+	for _, s := range common.Severities {
+		out = append(out, severityCount{Severity: s, Count: counts[s]})
+	}
+	return out
+}
+
+type severityCount struct {
+	Severity string
+	Count    int
+}
+
+// maxListedPayloads bounds the payloads listed for a clustered result
+const maxListedPayloads = 10
+
+// reason explains a result, with the payloads of a clustered result
+func reason(r common.ScanResult) string {
+	text := r.Reason
+	if text == "" {
+		text = r.Detail
+	}
+	if r.Count < 2 {
+		return text
+	}
+	payloads := r.Payloads
+	more := ""
+	if len(payloads) > maxListedPayloads {
+		more = fmt.Sprintf(", +%d more", len(payloads)-maxListedPayloads)
+		payloads = payloads[:maxListedPayloads]
+	}
+	return fmt.Sprintf("%s (%d results: %s%s)", text, r.Count, strings.Join(payloads, ", "), more)
+}
+
+// evidence is a short description of what the response looked like
+func evidence(r common.ScanResult) string {
+	var parts []string
+	if r.StatusCode > 0 {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))))
+	}
+	parts = append(parts, fmt.Sprintf("%d bytes", r.ContentLength))
+	if r.Title != "" {
+		parts = append(parts, fmt.Sprintf("title %q", r.Title))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+var testMeta = Meta{
+	Tool:     "httpsuite",
+	Version:  "1.0.0",
+	Started:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	Finished: time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC),
+}
+
+func testResults() []common.ScanResult {
+	return []common.ScanResult{
+		{Module: "preflight", Host: &common.Host{Origin: "https://example.com", Alive: true, Server: "nginx"}},
+		{URL: "https://example.com/admin", Target: "https://example.com/admin", Method: "GET", StatusCode: 403, Module: "bypass", Detail: "default request"},
+		{URL: "https://example.com/admin/", Target: "https://example.com/admin", Method: "GET", StatusCode: 200, ContentLength: 512,
+			Module: "bypass", Detail: "path -> status 403 -> 200", Reason: "status 403 -> 200", Title: "Admin | Panel", Fingerprint: "abc123",
+			Severity: "medium", Confidence: "firm"},
+		{URL: "https://example.com/", Target: "https://example.com/", Method: "GET", StatusCode: 200, Module: "cors",
+			Detail: "Origin: https://evil.com → reflected <origin>", Vulnerable: true},
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	results := testResults()
+	want := []string{"info", "info", "medium", "high"}
+	for i, r := range results {
+		if got := Severity(r); got != want[i] {
+			t.Fatalf("result %d: expected %s, got %s", i, want[i], got)
+		}
+	}
+	if IsFinding(results[0]) || IsFinding(results[1]) || !IsFinding(results[2]) {
+		t.Fatal("unexpected IsFinding classification")
+	}
+}
+
+func TestGroupByTargetAndModule(t *testing.T) {
+	groups := group(testResults())
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 target groups, got %+v", groups)
+	}
+	admin := groups[0]
+	if admin.Target != "https://example.com/admin" || len(admin.Modules) != 1 || admin.Modules[0].Module != "bypass" {
+		t.Fatalf("unexpected first group: %+v", admin)
+	}
+	// The bypass candidate sorts ahead of the informational default request
+	if admin.Modules[0].Results[0].Reason == "" {
+		t.Fatalf("expected the most severe result first, got %+v", admin.Modules[0].Results)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, testResults(), testMeta); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 2 || len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("expected 2 findings and 2 rules, got %d and %d", len(run.Results), len(run.Tool.Driver.Rules))
+	}
+	bypass := run.Results[0]
+	if bypass.RuleID != "httpsuite/bypass" || bypass.Level != "warning" {
+		t.Fatalf("unexpected bypass result: %+v", bypass)
+	}
+	if bypass.PartialFingerprints["responseHash/v1"] != "abc123" || bypass.Properties["reason"] != "status 403 -> 200" {
+		t.Fatalf("missing fingerprint or reason: %+v", bypass)
+	}
+	if run.Results[1].Level != "error" {
+		t.Fatalf("expected vulnerable CORS result as error, got %s", run.Results[1].Level)
+	}
+	if len(run.Invocations) != 1 || run.Invocations[0].StartTimeUTC != "2024-05-01T10:00:00Z" {
+		t.Fatalf("unexpected invocations: %+v", run.Invocations)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, testResults(), testMeta); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"| high | 1 |",
+		"| medium | 1 |",
+		"| medium (firm) | GET |",
+		"## Hosts",
+		"| https://example.com | yes |",
+		"### https://example.com/admin",
+		"#### bypass",
+		`title "Admin \| Panel"`,
+		"`abc123`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in markdown:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "### https://example.com/admin") > strings.Index(out, "### https://example.com/\n") {
+		t.Fatal("expected targets in first-seen order")
+	}
+}
+
+func TestWriteHTMLIsSelfContainedAndEscaped(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, testResults(), testMeta); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<style>", "https://example.com/admin", "sev-medium", "abc123", "&lt;origin&gt;"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in HTML report", want)
+		}
+	}
+	for _, unwanted := range []string{"<origin>", "<script", "<link", "src=\"http"} {
+		if strings.Contains(out, unwanted) {
+			t.Fatalf("unexpected %q in HTML report", unwanted)
+		}
+	}
+}
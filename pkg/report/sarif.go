@@ -0,0 +1,179 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aether-0/httpsuite/pkg/common"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/aether-0/httpsuite"
+)
+
+// ruleDescriptions document each module as a SARIF rule
+var ruleDescriptions = map[string]string{
+	"bypass":  "Access control bypass on a 401/403 protected resource",
+	"crlf":    "CRLF injection: injected header reflected in the response",
+	"cors":    "CORS misconfiguration allowing untrusted origins",
+	"methods": "Dangerous or unexpected HTTP method enabled",
+	"smuggle": "HTTP request smuggling via HTTP/2 downgrade",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	StartTimeUTC        string `json:"startTimeUtc,omitempty"`
+	EndTimeUTC          string `json:"endTimeUtc,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// WriteSARIF writes the findings among results as a SARIF 2.1.0 log, one
+// rule per module. Informational results are left out.
+func WriteSARIF(w io.Writer, results []common.ScanResult, meta Meta) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           meta.Tool,
+			Version:        meta.Version,
+			InformationURI: toolURI,
+		}},
+		Results: []sarifResult{},
+	}
+	if !meta.Started.IsZero() {
+		run.Invocations = []sarifInvocation{{
+			ExecutionSuccessful: true,
+			StartTimeUTC:        meta.Started.UTC().Format("2006-01-02T15:04:05Z"),
+			EndTimeUTC:          meta.Finished.UTC().Format("2006-01-02T15:04:05Z"),
+		}}
+	}
+
+	seenRules := make(map[string]bool)
+	for _, r := range results {
+		if !IsFinding(r) {
+			continue
+		}
+		ruleID := "httpsuite/" + r.Module
+		if !seenRules[ruleID] {
+			seenRules[ruleID] = true
+			description := ruleDescriptions[r.Module]
+			if description == "" {
+				description = r.Module + " finding"
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               ruleID,
+				Name:             r.Module,
+				ShortDescription: sarifMessage{Text: description},
+			})
+		}
+
+		message := r.Detail
+		if message == "" {
+			message = ruleDescriptions[r.Module]
+		}
+		result := sarifResult{
+			RuleID:    ruleID,
+			Level:     sarifLevel(Severity(r)),
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.URL}}}},
+			Properties: map[string]any{
+				"severity":       Severity(r),
+				"method":         r.Method,
+				"status_code":    r.StatusCode,
+				"content_length": r.ContentLength,
+			},
+		}
+		if r.Target != "" {
+			result.Properties["target"] = r.Target
+		}
+		if r.Confidence != "" {
+			result.Properties["confidence"] = r.Confidence
+		}
+		if r.Reason != "" {
+			result.Properties["reason"] = r.Reason
+		}
+		if r.Technique != "" {
+			result.Properties["technique"] = r.Technique
+			result.Properties["payload"] = r.Payload
+		}
+		if r.Count > 1 {
+			result.Properties["count"] = r.Count
+			result.Properties["payloads"] = r.Payloads
+		}
+		if r.Fingerprint != "" {
+			result.PartialFingerprints = map[string]string{"responseHash/v1": r.Fingerprint}
+		}
+		run.Results = append(run.Results, result)
+	}
+	if run.Tool.Driver.Rules == nil {
+		run.Tool.Driver.Rules = []sarifRule{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case common.SeverityCritical, common.SeverityHigh:
+		return "error"
+	case common.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
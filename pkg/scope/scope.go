@@ -0,0 +1,268 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrOutOfScope is returned for requests and dials that the scope rejects
+var ErrOutOfScope = errors.New("out of scope")
+
+// HostHeaders can redirect a request to another backend, so their values
+// are checked like the request host.
+var HostHeaders = []string{
+	"Host",
+	"X-Forwarded-Host",
+	"X-Forwarded-Server",
+	"X-Host",
+	"X-HTTP-Host-Override",
+	"X-Original-Host",
+	"Proxy-Host",
+}
+
+// DialFunc matches net.Dialer.DialContext
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Scope decides which hosts and paths may be contacted. When include rules
+// exist a request must match them; exclude rules always win.
+type Scope struct {
+	include rules
+	exclude rules
+
+	mu     sync.Mutex
+	logged map[string]bool
+	onSkip func(reason string)
+}
+
+type rules struct {
+	hosts []string
+	nets  []*net.IPNet
+	paths []*regexp.Regexp
+}
+
+// New parses include and exclude rules. A rule is a host glob
+// (*.example.com), an IP or CIDR (10.0.0.0/8), a URL whose host is used, or
+// path:<regex> matched against the request path.
+func New(include, exclude []string) (*Scope, error) {
+	s := &Scope{logged: make(map[string]bool)}
+	for _, rule := range include {
+		if err := s.include.add(rule); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range exclude {
+		if err := s.exclude.add(rule); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (r *rules) add(rule string) error {
+	rule = strings.TrimSpace(rule)
+	switch {
+	case rule == "":
+		return nil
+	case strings.HasPrefix(rule, "path:"):
+		re, err := regexp.Compile(strings.TrimPrefix(rule, "path:"))
+		if err != nil {
+			return fmt.Errorf("invalid scope path regex %q: %w", rule, err)
+		}
+		r.paths = append(r.paths, re)
+		return nil
+	case strings.Contains(rule, "://"):
+		parsed, err := url.Parse(rule)
+		if err != nil || parsed.Hostname() == "" {
+			return fmt.Errorf("invalid scope URL %q", rule)
+		}
+		rule = parsed.Hostname()
+	}
+
+	if _, network, err := net.ParseCIDR(rule); err == nil {
+		r.nets = append(r.nets, network)
+		return nil
+	}
+	if ip := net.ParseIP(strings.Trim(rule, "[]")); ip != nil {
+		bits := 8 * len(ip.To16())
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		r.nets = append(r.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		return nil
+	}
+
+	host := strings.ToLower(rule)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if _, err := path.Match(host, ""); err != nil {
+		return fmt.Errorf("invalid scope host pattern %q: %w", rule, err)
+	}
+	r.hosts = append(r.hosts, host)
+	return nil
+}
+
+func (r *rules) matchHost(host string) bool {
+	for _, pattern := range r.hosts {
+		if ok, _ := path.Match(pattern, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rules) matchIP(ip net.IP) bool {
+	for _, network := range r.nets {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rules) matchPath(u *url.URL) bool {
+	for _, re := range r.paths {
+		if re.MatchString(u.Path) || re.MatchString(u.EscapedPath()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty reports whether the scope has no rules at all
+func (s *Scope) Empty() bool {
+	if s == nil {
+		return true
+	}
+	return len(s.include.hosts)+len(s.include.nets)+len(s.include.paths)+
+		len(s.exclude.hosts)+len(s.exclude.nets)+len(s.exclude.paths) == 0
+}
+
+// OnSkip registers a callback invoked once per distinct out-of-scope reason
+func (s *Scope) OnSkip(fn func(reason string)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.onSkip = fn
+	s.mu.Unlock()
+}
+
+// Check reports whether a URL's host and path are in scope. Hostnames can
+// only be matched against CIDR rules once resolved; see DialContext.
+func (s *Scope) Check(u *url.URL) error {
+	if s.Empty() {
+		return nil
+	}
+	if err := s.CheckHost(u.Hostname()); err != nil {
+		return err
+	}
+
+	if s.exclude.matchPath(u) {
+		return s.reject(u.Hostname()+" path", fmt.Sprintf("%s%s matches an excluded path", u.Host, u.Path))
+	}
+	if len(s.include.paths) > 0 && !s.include.matchPath(u) {
+		return s.reject(u.Hostname()+" path", fmt.Sprintf("%s%s is outside the included paths", u.Host, u.Path))
+	}
+	return nil
+}
+
+// CheckHost applies the host rules to a bare host name or IP address
+func (s *Scope) CheckHost(host string) error {
+	if s.Empty() {
+		return nil
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	ip := net.ParseIP(host)
+
+	if s.exclude.matchHost(host) || (ip != nil && s.exclude.matchIP(ip)) {
+		return s.reject(host, "host "+host+" is excluded")
+	}
+	if len(s.include.hosts)+len(s.include.nets) == 0 || s.include.matchHost(host) {
+		return nil
+	}
+	if ip != nil {
+		if s.include.matchIP(ip) {
+			return nil
+		}
+	} else if len(s.include.nets) > 0 {
+		// Decided at dial time against the resolved addresses
+		return nil
+	}
+	return s.reject(host, "host "+host+" is not in scope")
+}
+
+// checkIP applies CIDR rules to an address host resolved to
+func (s *Scope) checkIP(host string, ip net.IP) error {
+	if s.exclude.matchIP(ip) {
+		return s.reject(host, fmt.Sprintf("host %s resolves to excluded address %s", host, ip))
+	}
+	if len(s.include.nets) == 0 || s.include.matchHost(host) || s.include.matchIP(ip) {
+		return nil
+	}
+	return s.reject(host, fmt.Sprintf("host %s resolves to %s, which is not in scope", host, ip))
+}
+
+// Resolve checks host and returns its addresses once they pass the CIDR
+// rules. IP literals are returned as-is.
+func (s *Scope) Resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if err := s.CheckHost(host); err != nil {
+		return nil, err
+	}
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if err := s.checkIP(host, ip); err != nil {
+			return nil, err
+		}
+	}
+	return ips, nil
+}
+
+// NeedsResolve reports whether CIDR rules require hosts to be resolved
+func (s *Scope) NeedsResolve() bool {
+	return !s.Empty() && len(s.include.nets)+len(s.exclude.nets) > 0
+}
+
+// DialContext wraps a dialer so that every resolved address is checked
+// against the CIDR rules before connecting. It must not wrap proxy dials.
+func (s *Scope) DialContext(next DialFunc) DialFunc {
+	if !s.NeedsResolve() {
+		return next
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := s.Resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		// Dial the checked address so a second lookup cannot change it
+		return next(ctx, network, net.JoinHostPort(ips[0].String(), port))
+	}
+}
+
+// reject logs the first skip for key and returns ErrOutOfScope
+func (s *Scope) reject(key, reason string) error {
+	s.mu.Lock()
+	first := !s.logged[key]
+	s.logged[key] = true
+	onSkip := s.onSkip
+	s.mu.Unlock()
+
+	if first && onSkip != nil {
+		onSkip(reason)
+	}
+	return fmt.Errorf("%w: %s", ErrOutOfScope, reason)
+}
@@ -0,0 +1,127 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %s: %v", raw, err)
+	}
+	return u
+}
+
+func TestCheckHostAndPathRules(t *testing.T) {
+	s, err := New(
+		[]string{"*.example.com", "https://api.example.org/v1"},
+		[]string{"admin.example.com", "path:^/logout", "10.0.0.5"},
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	cases := map[string]bool{
+		"https://app.example.com/admin":    true,
+		"https://a.b.example.com/":         true,
+		"https://api.example.org/":         true,
+		"https://example.com/":             false,
+		"https://evil.com/":                false,
+		"https://admin.example.com/":       false,
+		"https://app.example.com/logout":   false,
+		"https://app.example.com/%6cogout": false,
+		"http://10.0.0.5/":                 false,
+		"http://192.168.0.1/":              false,
+	}
+	for raw, want := range cases {
+		err := s.Check(mustParse(t, raw))
+		if (err == nil) != want {
+			t.Errorf("Check(%s) = %v, want in scope %v", raw, err, want)
+		}
+		if err != nil && !errors.Is(err, ErrOutOfScope) {
+			t.Errorf("Check(%s) returned %v, want ErrOutOfScope", raw, err)
+		}
+	}
+}
+
+func TestCIDRRulesDeferHostnamesToDial(t *testing.T) {
+	s, _ := New([]string{"10.0.0.0/8"}, nil)
+	if err := s.Check(mustParse(t, "http://10.1.2.3:8080/")); err != nil {
+		t.Fatalf("expected an address inside the CIDR to be in scope: %v", err)
+	}
+	if err := s.Check(mustParse(t, "http://192.168.0.1/")); err == nil {
+		t.Fatalf("expected an address outside the CIDR to be rejected")
+	}
+	if err := s.Check(mustParse(t, "https://intranet.example.com/")); err != nil {
+		t.Fatalf("expected hostnames to be decided at dial time: %v", err)
+	}
+	if _, err := s.Resolve(context.Background(), "127.0.0.1"); !errors.Is(err, ErrOutOfScope) {
+		t.Fatalf("expected a resolved address outside the CIDR to be rejected, got %v", err)
+	}
+}
+
+func TestIncludePathsAndEncodedPaths(t *testing.T) {
+	s, err := New([]string{"path:^/api/"}, []string{"path:(?i)/delete"})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if err := s.Check(mustParse(t, "https://any.host/api/users")); err != nil {
+		t.Fatalf("expected /api/ to be in scope: %v", err)
+	}
+	if err := s.Check(mustParse(t, "https://any.host/static/app.js")); err == nil {
+		t.Fatalf("expected paths outside /api/ to be rejected")
+	}
+	if err := s.Check(mustParse(t, "https://any.host/api/%44elete")); err == nil {
+		t.Fatalf("expected the decoded path to match the exclude rule")
+	}
+}
+
+func TestOnSkipLogsOncePerReason(t *testing.T) {
+	s, _ := New([]string{"example.com"}, nil)
+	var logged []string
+	s.OnSkip(func(reason string) { logged = append(logged, reason) })
+
+	for i := 0; i < 3; i++ {
+		_ = s.Check(mustParse(t, "https://evil.com/"))
+	}
+	_ = s.Check(mustParse(t, "https://other.com/"))
+
+	if len(logged) != 2 {
+		t.Fatalf("expected one log line per host, got %v", logged)
+	}
+}
+
+func TestDialContextChecksResolvedAddresses(t *testing.T) {
+	s, _ := New(nil, []string{"127.0.0.0/8"})
+	dialed := false
+	dial := s.DialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = true
+		return nil, nil
+	})
+
+	_, err := dial(context.Background(), "tcp", "localhost:80")
+	if !errors.Is(err, ErrOutOfScope) || dialed {
+		t.Fatalf("expected localhost to be rejected before dialing, got %v (dialed=%v)", err, dialed)
+	}
+}
+
+func TestNewRejectsInvalidRules(t *testing.T) {
+	if _, err := New([]string{"path:("}, nil); err == nil {
+		t.Fatalf("expected an invalid regex to be rejected")
+	}
+	if _, err := New(nil, []string{"[bad"}); err == nil {
+		t.Fatalf("expected an invalid glob to be rejected")
+	}
+}
+
+func TestNilScopeAllowsEverything(t *testing.T) {
+	var s *Scope
+	if !s.Empty() || s.Check(mustParse(t, "https://anything/")) != nil {
+		t.Fatalf("expected a nil scope to allow every request")
+	}
+}
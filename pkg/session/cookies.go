@@ -0,0 +1,80 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadCookieFile reads a Netscape/Mozilla cookies.txt file, as exported by
+// browsers and curl, into the jar.
+func loadCookieFile(jar http.CookieJar, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("error reading cookie file: %w", err)
+	}
+
+	// utils.ReadLines would drop the #HttpOnly_ lines, so split by hand.
+	loaded := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		cookie, cookieURL, err := parseCookieLine(line)
+		if err != nil {
+			return loaded, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		if cookie == nil {
+			continue
+		}
+		jar.SetCookies(cookieURL, []*http.Cookie{cookie})
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// parseCookieLine parses one tab-separated cookies.txt line. Comments and
+// blank lines return a nil cookie.
+func parseCookieLine(line string) (*http.Cookie, *url.URL, error) {
+	httpOnly := false
+	if strings.HasPrefix(line, "#HttpOnly_") {
+		httpOnly = true
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+	}
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+		return nil, nil, nil
+	}
+
+	fields := strings.Split(line, "\t")
+	if len(fields) != 7 {
+		return nil, nil, fmt.Errorf("expected 7 tab-separated fields, got %d", len(fields))
+	}
+
+	domain := fields[0]
+	includeSubdomains := strings.EqualFold(fields[1], "TRUE")
+	secure := strings.EqualFold(fields[3], "TRUE")
+
+	cookie := &http.Cookie{
+		Name:     fields[5],
+		Value:    fields[6],
+		Path:     fields[2],
+		Secure:   secure,
+		HttpOnly: httpOnly,
+	}
+	if includeSubdomains {
+		cookie.Domain = domain
+	}
+	if expiry, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expiry > 0 {
+		cookie.Expires = time.Unix(expiry, 0)
+	}
+
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
+	return cookie, &url.URL{Scheme: scheme, Host: strings.TrimPrefix(domain, "."), Path: "/"}, nil
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matcher decides whether a response matches a login-success or logged-out
+// fingerprint. Expressions take one of three forms:
+//
+//	status:302,303        response status is one of the listed codes
+//	header:Location:login  header value matches the regular expression
+//	<regex>                response body matches the regular expression
+type Matcher struct {
+	statuses []int
+	header   string
+	pattern  *regexp.Regexp
+}
+
+// ParseMatcher compiles a matcher expression.
+func ParseMatcher(expr string) (*Matcher, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty matcher")
+	}
+
+	switch {
+	case strings.HasPrefix(expr, "status:"):
+		m := &Matcher{}
+		for _, field := range strings.Split(strings.TrimPrefix(expr, "status:"), ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return nil, fmt.Errorf("invalid status code %q in matcher", field)
+			}
+			m.statuses = append(m.statuses, code)
+		}
+		return m, nil
+	case strings.HasPrefix(expr, "header:"):
+		name, pattern, ok := strings.Cut(strings.TrimPrefix(expr, "header:"), ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header matcher %q: expected header:Name:regex", expr)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header matcher pattern: %w", err)
+		}
+		return &Matcher{header: strings.TrimSpace(name), pattern: re}, nil
+	default:
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body matcher pattern: %w", err)
+		}
+		return &Matcher{pattern: re}, nil
+	}
+}
+
+// NeedsBody reports whether the matcher inspects the response body.
+func (m *Matcher) NeedsBody() bool {
+	return m != nil && len(m.statuses) == 0 && m.header == ""
+}
+
+// Match reports whether the response matches.
+func (m *Matcher) Match(statusCode int, header http.Header, body []byte) bool {
+	if m == nil {
+		return false
+	}
+
+	if len(m.statuses) > 0 {
+		for _, code := range m.statuses {
+			if code == statusCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	if m.header != "" {
+		for _, value := range header.Values(m.header) {
+			if m.pattern.MatchString(value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return m.pattern.Match(body)
+}
@@ -0,0 +1,445 @@
+package session
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aether-0/httpsuite/pkg/scope"
+	"github.com/aether-0/httpsuite/pkg/utils"
+)
+
+// tokenRefreshMargin renews bearer tokens shortly before they expire
+const tokenRefreshMargin = 30 * time.Second
+
+// renewBackoff is how long a failed login or token refresh blocks new attempts
+const renewBackoff = 5 * time.Second
+
+// maxAuthResponseBytes bounds how much of a login or token response is read
+const maxAuthResponseBytes = 1 << 20
+
+// Request describes a scripted login or token request
+type Request struct {
+	URL         string
+	Method      string
+	Body        string
+	ContentType string
+}
+
+// Options configures an authenticated session
+type Options struct {
+	Cookie       string // raw Cookie header value added to every request
+	CookieFile   string // Netscape cookies.txt file
+	Login        *Request
+	LoginSuccess *Matcher
+	LoggedOut    *Matcher
+	Token        *Request
+	TokenField   string // dotted JSON path of the token, default access_token
+
+	Timeout   time.Duration
+	Proxy     *url.URL
+	UserAgent string
+	Headers   map[string]string
+
+	// Scope is checked before every login and token request
+	Scope *scope.Scope
+}
+
+// Session holds cookies and bearer tokens shared by all scanners of a run and
+// renews them when the target logs the scanner out. A nil *Session is valid
+// and adds nothing to requests.
+type Session struct {
+	opts   Options
+	jar    *cookiejar.Jar
+	client *http.Client
+
+	mu          sync.RWMutex
+	token       string
+	tokenExpiry time.Time
+	generation  uint64
+
+	renewMu       sync.Mutex
+	renewFailedAt time.Time
+	onRenew       func(err error)
+}
+
+// New creates a session and loads any cookie file. No request is sent until Start.
+func New(opts Options) (*Session, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CookieFile != "" {
+		if _, err := loadCookieFile(jar, opts.CookieFile); err != nil {
+			return nil, err
+		}
+	}
+	if opts.TokenField == "" {
+		opts.TokenField = "access_token"
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		DialContext:     opts.Scope.DialContext(dialer.DialContext),
+	}
+	if opts.Proxy != nil {
+		// The proxy resolves hosts, so only URL rules apply
+		transport.Proxy = http.ProxyURL(opts.Proxy)
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &Session{
+		opts: opts,
+		jar:  jar,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			Jar:       jar,
+			// Login success is often a redirect, so keep it observable
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Start runs the login flow and fetches the first bearer token, if configured.
+func (s *Session) Start() error {
+	if s == nil {
+		return nil
+	}
+	return s.authenticate()
+}
+
+// OnRenew registers a callback invoked after every re-login triggered by a
+// logged-out response, with the outcome of that login.
+func (s *Session) OnRenew(fn func(err error)) {
+	if s != nil {
+		s.onRenew = fn
+	}
+}
+
+// Generation identifies the current credentials; it changes on every renewal.
+func (s *Session) Generation() uint64 {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.generation
+}
+
+// CanRenew reports whether logged-out responses are detected and renewed.
+func (s *Session) CanRenew() bool {
+	return s != nil && s.opts.LoggedOut != nil
+}
+
+// NeedsBody reports whether logged-out detection inspects the response body.
+func (s *Session) NeedsBody() bool {
+	return s != nil && s.opts.LoggedOut.NeedsBody()
+}
+
+// LoggedOut reports whether a response matches the logged-out fingerprint.
+func (s *Session) LoggedOut(statusCode int, header http.Header, body []byte) bool {
+	return s.CanRenew() && s.opts.LoggedOut.Match(statusCode, header, body)
+}
+
+// Renew logs in again unless another goroutine already renewed the
+// credentials since generation was observed.
+func (s *Session) Renew(generation uint64) error {
+	if s == nil {
+		return nil
+	}
+
+	s.renewMu.Lock()
+	defer s.renewMu.Unlock()
+
+	if s.Generation() != generation {
+		return nil
+	}
+	if time.Since(s.renewFailedAt) < renewBackoff {
+		return fmt.Errorf("re-login suppressed after a recent failure")
+	}
+
+	err := s.authenticate()
+	if err != nil {
+		s.renewFailedAt = time.Now()
+	}
+	if s.onRenew != nil {
+		s.onRenew(err)
+	}
+	return err
+}
+
+// Apply returns a copy of req carrying the session cookies and bearer token.
+// The original request is left untouched so it can be re-applied after renewal.
+func (s *Session) Apply(req *http.Request) *http.Request {
+	if s == nil {
+		return req
+	}
+
+	cookie, authorization := s.credentials(req.URL)
+	if cookie == "" && authorization == "" {
+		return req
+	}
+
+	clone := req.Clone(req.Context())
+	if cookie != "" {
+		if existing := clone.Header.Get("Cookie"); existing != "" {
+			cookie = existing + "; " + cookie
+		}
+		clone.Header.Set("Cookie", cookie)
+	}
+	if authorization != "" {
+		clone.Header.Set("Authorization", authorization)
+	}
+	return clone
+}
+
+// MergeHeaders returns a copy of headers with the session credentials for
+// targetURL added, for senders that write raw requests.
+func (s *Session) MergeHeaders(targetURL string, headers map[string]string) map[string]string {
+	merged := make(map[string]string, len(headers)+2)
+	for k, v := range headers {
+		merged[k] = v
+	}
+	if s == nil {
+		return merged
+	}
+
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return merged
+	}
+
+	cookie, authorization := s.credentials(parsed)
+	if cookie != "" {
+		for k, v := range merged {
+			if strings.EqualFold(k, "Cookie") {
+				delete(merged, k)
+				cookie = v + "; " + cookie
+			}
+		}
+		merged["Cookie"] = cookie
+	}
+	if authorization != "" {
+		for k := range merged {
+			if strings.EqualFold(k, "Authorization") {
+				delete(merged, k)
+			}
+		}
+		merged["Authorization"] = authorization
+	}
+	return merged
+}
+
+func (s *Session) credentials(u *url.URL) (cookie, authorization string) {
+	s.refreshExpiredToken()
+
+	// Cookies issued by a login win over static ones of the same name
+	var parts []string
+	issued := make(map[string]bool)
+	for _, c := range s.jar.Cookies(u) {
+		parts = append(parts, c.Name+"="+c.Value)
+		issued[c.Name] = true
+	}
+	for _, pair := range strings.Split(s.opts.Cookie, ";") {
+		pair = strings.TrimSpace(pair)
+		name, _, _ := strings.Cut(pair, "=")
+		if pair != "" && !issued[name] {
+			parts = append(parts, pair)
+		}
+	}
+	cookie = strings.Join(parts, "; ")
+
+	s.mu.RLock()
+	if s.token != "" {
+		authorization = "Bearer " + s.token
+	}
+	s.mu.RUnlock()
+
+	return cookie, authorization
+}
+
+// refreshExpiredToken fetches a new token when the current one is about to
+// expire. Failures keep the old token; the logged-out matcher still applies.
+func (s *Session) refreshExpiredToken() {
+	if s.opts.Token == nil {
+		return
+	}
+
+	s.mu.RLock()
+	expiry := s.tokenExpiry
+	s.mu.RUnlock()
+	if expiry.IsZero() || time.Until(expiry) > tokenRefreshMargin {
+		return
+	}
+
+	s.renewMu.Lock()
+	defer s.renewMu.Unlock()
+
+	s.mu.RLock()
+	stillExpiring := s.tokenExpiry.Equal(expiry)
+	s.mu.RUnlock()
+	if !stillExpiring {
+		return
+	}
+
+	if err := s.fetchToken(); err != nil {
+		// Keep the old token and retry after the backoff instead of on every request
+		s.mu.Lock()
+		s.tokenExpiry = time.Now().Add(tokenRefreshMargin + renewBackoff)
+		s.mu.Unlock()
+	}
+}
+
+func (s *Session) authenticate() error {
+	if s.opts.Login != nil {
+		if err := s.login(); err != nil {
+			return err
+		}
+	}
+	if s.opts.Token != nil {
+		if err := s.fetchToken(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.generation++
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Session) login() error {
+	statusCode, header, body, err := s.send(s.opts.Login)
+	if err != nil {
+		return fmt.Errorf("login request failed: %w", err)
+	}
+
+	if s.opts.LoginSuccess != nil {
+		if !s.opts.LoginSuccess.Match(statusCode, header, body) {
+			return fmt.Errorf("login failed: response (status %d) did not match the success matcher", statusCode)
+		}
+	} else if statusCode >= 400 {
+		return fmt.Errorf("login failed with status %d", statusCode)
+	}
+
+	return nil
+}
+
+func (s *Session) fetchToken() error {
+	statusCode, _, body, err := s.send(s.opts.Token)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+	if statusCode >= 400 {
+		return fmt.Errorf("token request failed with status %d", statusCode)
+	}
+
+	token, expiresIn, err := extractToken(body, s.opts.TokenField)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.token = token
+	s.tokenExpiry = time.Time{}
+	if expiresIn > 0 {
+		s.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Session) send(r *Request) (int, http.Header, []byte, error) {
+	method := r.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(r.Body)
+	}
+	req, err := http.NewRequest(method, r.URL, body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if err := s.opts.Scope.Check(req.URL); err != nil {
+		return 0, nil, nil, err
+	}
+
+	for k, v := range s.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if s.opts.UserAgent != "" {
+		req.Header.Set("User-Agent", s.opts.UserAgent)
+	}
+	if r.Body != "" {
+		contentType := r.ContentType
+		if contentType == "" {
+			contentType = utils.GuessContentType([]byte(r.Body))
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.opts.Cookie != "" {
+		req.Header.Set("Cookie", s.opts.Cookie)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAuthResponseBytes))
+	if err != nil {
+		return resp.StatusCode, resp.Header, nil, err
+	}
+	return resp.StatusCode, resp.Header, data, nil
+}
+
+// extractToken reads the token at a dotted JSON path and an optional
+// top-level expires_in from a token response.
+func extractToken(body []byte, field string) (string, int64, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", 0, fmt.Errorf("token response is not JSON: %w", err)
+	}
+
+	var current any = doc
+	for _, key := range strings.Split(field, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return "", 0, fmt.Errorf("token field %q not found in response", field)
+		}
+		current = obj[key]
+	}
+
+	token, ok := current.(string)
+	if !ok || token == "" {
+		return "", 0, fmt.Errorf("token field %q not found in response", field)
+	}
+
+	var expiresIn int64
+	if v, ok := doc["expires_in"].(float64); ok {
+		expiresIn = int64(v)
+	}
+	return token, expiresIn, nil
+}
@@ -0,0 +1,164 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aether-0/httpsuite/pkg/scope"
+)
+
+func TestNetscapeCookieFileIsApplied(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	content := "# Netscape HTTP Cookie File\n" +
+		"#HttpOnly_.example.com\tTRUE\t/\tTRUE\t0\tsid\tabc123\n" +
+		"api.example.com\tFALSE\t/v1\tFALSE\t0\tlang\ten\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write cookie file: %v", err)
+	}
+
+	sess, err := New(Options{CookieFile: path, Cookie: "static=1"})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/v1/users", nil)
+	got := sess.Apply(req).Header.Get("Cookie")
+	for _, want := range []string{"static=1", "sid=abc123", "lang=en"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected cookie header to contain %q, got %q", want, got)
+		}
+	}
+	if req.Header.Get("Cookie") != "" {
+		t.Fatalf("Apply must not modify the original request")
+	}
+
+	other, _ := http.NewRequest(http.MethodGet, "https://other.example.org/", nil)
+	if got := sess.Apply(other).Header.Get("Cookie"); got != "static=1" {
+		t.Fatalf("expected only the static cookie for another host, got %q", got)
+	}
+}
+
+func TestLoginAndTokenRefresh(t *testing.T) {
+	var logins, tokens int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			atomic.AddInt32(&logins, 1)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", Path: "/"})
+			w.Header().Set("Location", "/dashboard")
+			w.WriteHeader(http.StatusFound)
+		case "/token":
+			atomic.AddInt32(&tokens, 1)
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "s1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"jwt":"tok"},"expires_in":3600}`))
+		}
+	}))
+	defer server.Close()
+
+	success, _ := ParseMatcher("status:302")
+	loggedOut, _ := ParseMatcher("header:Location:/login")
+	sess, err := New(Options{
+		Login:        &Request{URL: server.URL + "/login", Body: "user=a&pass=b"},
+		LoginSuccess: success,
+		LoggedOut:    loggedOut,
+		Token:        &Request{URL: server.URL + "/token"},
+		TokenField:   "data.jwt",
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if err := sess.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api", nil)
+	applied := sess.Apply(req)
+	if applied.Header.Get("Authorization") != "Bearer tok" {
+		t.Fatalf("unexpected Authorization header %q", applied.Header.Get("Authorization"))
+	}
+	if !strings.Contains(applied.Header.Get("Cookie"), "session=s1") {
+		t.Fatalf("expected login cookie, got %q", applied.Header.Get("Cookie"))
+	}
+
+	header := http.Header{"Location": []string{"/login?next=/api"}}
+	if !sess.LoggedOut(http.StatusFound, header, nil) {
+		t.Fatalf("expected logged-out matcher to match")
+	}
+
+	generation := sess.Generation()
+	if err := sess.Renew(generation); err != nil {
+		t.Fatalf("Renew returned error: %v", err)
+	}
+	// A concurrent caller that saw the old generation must not log in again
+	if err := sess.Renew(generation); err != nil {
+		t.Fatalf("Renew returned error: %v", err)
+	}
+	if logins != 2 || tokens != 2 {
+		t.Fatalf("expected 2 logins and 2 token fetches, got %d and %d", logins, tokens)
+	}
+}
+
+func TestLoginFailsWhenSuccessMatcherDoesNotMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Invalid credentials"))
+	}))
+	defer server.Close()
+
+	success, _ := ParseMatcher("Welcome back")
+	sess, _ := New(Options{
+		Login:        &Request{URL: server.URL, Body: `{"user":"a"}`},
+		LoginSuccess: success,
+	})
+	if err := sess.Start(); err == nil {
+		t.Fatalf("expected login failure")
+	}
+}
+
+func TestParseMatcherRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "status:abc", "header:", "header:Location:(", "("} {
+		if _, err := ParseMatcher(expr); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func TestLoginOutsideScopeIsNotSent(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+
+	rules, err := scope.New([]string{"127.0.0.1"}, []string{"path:^/sso"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := New(Options{
+		Login: &Request{URL: server.URL + "/sso/login", Body: "user=a"},
+		Scope: rules,
+	})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if err := sess.Start(); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected an out-of-scope login to be refused, got %v", err)
+	}
+
+	sess.opts.Token = &Request{URL: "http://192.0.2.1:1/token"}
+	if err := sess.fetchToken(); !errors.Is(err, scope.ErrOutOfScope) {
+		t.Fatalf("expected an out-of-scope token request to be refused, got %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 0 {
+		t.Fatalf("expected no request to reach the server, got %d", got)
+	}
+}
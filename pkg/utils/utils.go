@@ -45,6 +45,46 @@ func ReadLines(path string) ([]string, error) {
 	return lines, scanner.Err()
 }
 
+// CanonicalURL lowercases the scheme and host, drops default ports,
+// fragments and empty queries, and gives an empty path a "/". Input without
+// a scheme is returned scheme-relative ("//host/path") so the caller can
+// probe which scheme the host speaks. Unparseable input is returned trimmed.
+func CanonicalURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return ""
+	}
+	if !strings.Contains(rawURL, "://") && !strings.HasPrefix(rawURL, "//") {
+		rawURL = "//" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if (u.Scheme == "https" && port == "443") || (u.Scheme == "http" && port == "80") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	u.Fragment, u.RawFragment = "", ""
+	u.ForceQuery = false
+	if u.Path == "" {
+		u.Path, u.RawPath = "/", ""
+	}
+	return u.String()
+}
+
 // ReadURLsFromStdin reads URLs from stdin
 func ReadURLsFromStdin() []string {
 	var urls []string
@@ -52,7 +92,7 @@ func ReadURLsFromStdin() []string {
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" {
-			urls = append(urls, NormalizeURL(line))
+			urls = append(urls, CanonicalURL(line))
 		}
 	}
 	return urls
@@ -237,3 +277,28 @@ func RandomUserAgent() string {
 	}
 	return agents[rand.Intn(len(agents))]
 }
+
+// GuessContentType picks JSON for bodies that look like JSON, XML for XML,
+// and form encoding otherwise
+func GuessContentType(body []byte) string {
+	trimmed := strings.TrimSpace(string(body))
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		return "application/json"
+	case strings.HasPrefix(trimmed, "<"):
+		return "application/xml"
+	default:
+		return "application/x-www-form-urlencoded"
+	}
+}
+
+// MethodAllowsBody reports whether a request body should be sent with method.
+// HEAD, TRACE and CONNECT requests never carry the configured body.
+func MethodAllowsBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case "HEAD", "TRACE", "CONNECT":
+		return false
+	default:
+		return true
+	}
+}
@@ -32,3 +32,34 @@ func TestGenerateCaseVariantsWithoutLetters(t *testing.T) {
 		t.Fatalf("unexpected variant: %q", variants[0])
 	}
 }
+
+func TestGuessContentType(t *testing.T) {
+	for body, want := range map[string]string{
+		` {"a":1}`:      "application/json",
+		`[1,2]`:         "application/json",
+		`<user/>`:       "application/xml",
+		`user=a&pass=b`: "application/x-www-form-urlencoded",
+	} {
+		if got := GuessContentType([]byte(body)); got != want {
+			t.Fatalf("GuessContentType(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	cases := map[string]string{
+		"  HTTPS://Example.COM:443  ":       "https://example.com/",
+		"http://example.com:80/a?b=1#frag":  "http://example.com/a?b=1",
+		"https://example.com:8443/admin?":   "https://example.com:8443/admin",
+		"https://example.com./%2e%2e/admin": "https://example.com/%2e%2e/admin",
+		"Example.com/admin":                 "//example.com/admin",
+		"10.0.0.1:8080":                     "//10.0.0.1:8080/",
+		"https://[::1]:443/":                "https://[::1]/",
+		"":                                  "",
+	}
+	for input, want := range cases {
+		if got := CanonicalURL(input); got != want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", input, got, want)
+		}
+	}
+}